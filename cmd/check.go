@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/reegnz/policy-bot-tests/internal/lint"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkOutputFormat string
+	checkPolicyFile   string
+	checkRemoteRoots  map[string]string
+)
+
+// NewCheckCommand creates the "check" subcommand
+func NewCheckCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [paths...]",
+		Short: "Checks test files for mistakes",
+		Long: "Checks test files for duplicate test names, assertions on rules the policy doesn't define " +
+			"and reviews by users who are not in any team, without evaluating the test cases.",
+		RunE: runCheck,
+	}
+
+	cmd.Flags().StringVarP(&checkOutputFormat, "output", "o", defaultOutput, "output format (pretty, efm, json)")
+	cmd.Flags().StringVarP(&checkPolicyFile, "policy", "p", defaultPolicyFile, "path to the default policy file, used by test files not declaring a policy")
+	cmd.Flags().StringToStringVar(&checkRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+
+	return cmd
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		args = []string{defaultTestPath}
+	}
+
+	tests, err := loader.LoadTestFiles(args)
+	if err != nil {
+		return withExitCode(ExitTestFileError, fmt.Errorf("failed to load tests: %w", err))
+	}
+	configs, err := loader.LoadPolicyConfigs(tests, checkPolicyFile, loader.PolicyOptions{RemoteRoots: checkRemoteRoots})
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load policy: %w", err))
+	}
+
+	issues := lint.CheckTests(tests, configs)
+	if issues == nil {
+		issues = []lint.Issue{}
+	}
+	if err := printIssues(issues, checkOutputFormat); err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return withExitCode(ExitFailed, nil)
+	}
+	return nil
+}
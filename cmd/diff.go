@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFilter       string
+	diffOutputFormat string
+	diffBasePolicy   string
+	diffHeadPolicy   string
+	diffRemoteRoots  map[string]string
+)
+
+// NewDiffCommand creates the "diff" subcommand
+func NewDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [paths...]",
+		Short: "Shows which test outcomes change between two policy files",
+		Long: "Evaluates every test case against a base and a head policy file and reports the test cases " +
+			"whose evaluation status or rule statuses change. Assertions and the policy declared by test files are ignored.",
+		RunE: runDiff,
+	}
+
+	cmd.Flags().StringVarP(&diffFilter, "filter", "f", "", "filter test cases by name using regex")
+	cmd.Flags().StringVarP(&diffOutputFormat, "output", "o", defaultOutput, "output format (pretty, efm)")
+	cmd.Flags().StringVar(&diffBasePolicy, "base", "", "path to the base policy file")
+	cmd.Flags().StringVar(&diffHeadPolicy, "head", defaultPolicyFile, "path to the head policy file")
+	cmd.Flags().StringToStringVar(&diffRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+	_ = cmd.MarkFlagRequired("base")
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	opts := loader.PolicyOptions{RemoteRoots: diffRemoteRoots}
+	base, err := loader.LoadPolicyEvaluator(diffBasePolicy, opts)
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load base evaluator: %w", err))
+	}
+	head, err := loader.LoadPolicyEvaluator(diffHeadPolicy, opts)
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load head evaluator: %w", err))
+	}
+
+	if len(args) == 0 {
+		args = []string{defaultTestPath}
+	}
+
+	tests, err := loader.LoadTestFiles(args)
+	if err != nil {
+		return withExitCode(ExitTestFileError, fmt.Errorf("failed to load tests: %w", err))
+	}
+	changed, err := runner.RunDiff(base, head, tests, diffFilter, diffOutputFormat)
+	if err != nil {
+		return fmt.Errorf("invalid filter regex: %w", err)
+	}
+	if changed {
+		return withExitCode(ExitFailed, nil)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import "errors"
+
+// Exit codes of the commands, so scripts can tell failing tests from broken setups
+const (
+	// ExitFailed means test cases failed, or checks found issues
+	ExitFailed = 1
+	// ExitConfigError means invalid flags, or a policy that can't be loaded
+	ExitConfigError = 2
+	// ExitTestFileError means a test file that can't be loaded, or has mistakes
+	ExitTestFileError = 3
+	// ExitNoTests means no test case was selected to run
+	ExitNoTests = 4
+)
+
+// exitError is an error terminating the command with a specific exit code.
+// An exitError without an underlying error terminates it without a message.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return ""
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps an error to terminate the command with the exit code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code for an error returned by a command.
+// Errors without an exit code are usage errors.
+func exitCode(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return ExitConfigError
+}
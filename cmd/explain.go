@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/reegnz/policy-bot-tests/internal/explain"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/output"
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	explainPolicyFile  string
+	explainRemoteRoots map[string]string
+)
+
+// NewExplainCommand creates the "explain" subcommand
+func NewExplainCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <test name> [paths...]",
+		Short: "Explains the evaluation of a single test case",
+		Long: "Evaluates a single test case and explains which rules applied, which predicates skipped the others, " +
+			"which reviews and comments were counted or ignored and why, and what would approve each pending rule.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: runExplain,
+	}
+
+	cmd.Flags().StringVarP(&explainPolicyFile, "policy", "p", defaultPolicyFile, "path to the default policy file, used by test files not declaring a policy")
+	cmd.Flags().StringToStringVar(&explainRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+
+	return cmd
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	name, paths := args[0], args[1:]
+	if len(paths) == 0 {
+		paths = []string{defaultTestPath}
+	}
+
+	tests, err := loader.LoadTestFiles(paths)
+	if err != nil {
+		return withExitCode(ExitTestFileError, fmt.Errorf("failed to load tests: %w", err))
+	}
+	var matches []models.TestCase
+	for _, tc := range tests.TestCases {
+		if tc.Name == name {
+			matches = append(matches, tc)
+		}
+	}
+	if len(matches) == 0 {
+		return withExitCode(ExitNoTests, fmt.Errorf("no test case named %q", name))
+	}
+
+	configs, err := loader.LoadPolicyConfigs(&models.TestFile{TestCases: matches}, explainPolicyFile, loader.PolicyOptions{RemoteRoots: explainRemoteRoots})
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load evaluator: %w", err))
+	}
+	evaluators, err := loader.ParsePolicyEvaluators(configs)
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load evaluator: %w", err))
+	}
+
+	for _, tc := range matches {
+		mergedContext := runner.MergeContexts(tc.DefaultContextOr(tests.DefaultContext), tc.Context)
+		e := explain.Explain(configs[tc.PolicyFile], evaluators[tc.PolicyFile], mergedContext)
+		output.PrintExplanation(tc, mergedContext, e)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/reegnz/policy-bot-tests/internal/generate"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	generatePolicyFile  string
+	generateOutputFile  string
+	generateOwner       string
+	generateRepo        string
+	generateRemoteRoots map[string]string
+)
+
+// NewGenerateCommand creates the "generate" subcommand
+func NewGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "generate",
+		Aliases: []string{"init"},
+		Short:   "Generates a test file skeleton from a policy file",
+		Long: "Generates a test file with a default context holding the teams required by the policy, " +
+			"and an approved and a pending test case for every approval rule, with assertions filled in from evaluating them.",
+		Args: cobra.NoArgs,
+		RunE: runGenerate,
+	}
+
+	cmd.Flags().StringVarP(&generatePolicyFile, "policy", "p", defaultPolicyFile, "path to the policy file")
+	cmd.Flags().StringVar(&generateOutputFile, "output-file", "", "write the test file to this path instead of stdout")
+	cmd.Flags().StringVar(&generateOwner, "owner", "", "repository owner of the default context (default: organization of the first required team)")
+	cmd.Flags().StringVar(&generateRepo, "repo", "", "repository name of the default context")
+	cmd.Flags().StringToStringVar(&generateRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+
+	return cmd
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	config, err := loader.LoadPolicyConfig(generatePolicyFile, loader.PolicyOptions{RemoteRoots: generateRemoteRoots})
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load policy: %w", err))
+	}
+
+	tests, err := generate.Generate(config, generate.Options{Owner: generateOwner, Repo: generateRepo})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(tests); err != nil {
+		return fmt.Errorf("failed to marshal tests: %w", err)
+	}
+
+	if generateOutputFile == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(generateOutputFile, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", generateOutputFile, err)
+	}
+	return nil
+}
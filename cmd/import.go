@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/reegnz/policy-bot-tests/internal/importer"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	importPolicyFile  string
+	importOutputFile  string
+	importName        string
+	importRemoteRoots map[string]string
+)
+
+// NewImportCommand creates the "import" subcommand
+func NewImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <dump-dir>",
+		Short: "Records a test case from a GitHub API dump of a pull request",
+		Long: "Records a test case from a directory of GitHub REST API payloads of a pull request " +
+			"(" + importer.PullFile + ", " + importer.FilesFile + ", " + importer.ReviewsFile + ", " + importer.CommentsFile + ", " +
+			importer.StatusesFile + ", " + importer.CheckRunsFile + ", " + importer.TeamsFile + ", " + importer.OrgsFile + "), " +
+			"with assertions filled in from evaluating it against the policy.",
+		Args: cobra.ExactArgs(1),
+		RunE: runImport,
+	}
+
+	cmd.Flags().StringVarP(&importPolicyFile, "policy", "p", defaultPolicyFile, "path to the policy file")
+	cmd.Flags().StringVar(&importOutputFile, "output-file", "", "write the test file to this path instead of stdout")
+	cmd.Flags().StringVar(&importName, "name", "", "name of the test case (default: owner/repo#number: title)")
+	cmd.Flags().StringToStringVar(&importRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+
+	return cmd
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	evaluator, err := loader.LoadPolicyEvaluator(importPolicyFile, loader.PolicyOptions{RemoteRoots: importRemoteRoots})
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load evaluator: %w", err))
+	}
+
+	tc, err := importer.Import(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", args[0], err)
+	}
+	if importName != "" {
+		tc.Name = importName
+	}
+	_, result := runner.EvaluateTestCase(evaluator, models.NewTestContext(models.TestContext{}), models.TestCase{Context: models.NewTestContext(tc.Context)})
+	tc.Assert = runner.ActualAssertion(&result)
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(models.TestFile{TestCases: []models.TestCase{tc}}); err != nil {
+		return fmt.Errorf("failed to marshal tests: %w", err)
+	}
+
+	if importOutputFile == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(importOutputFile, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", importOutputFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/reegnz/policy-bot-tests/internal/lint"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintOutputFormat string
+	lintPolicyFile   string
+	lintRemoteRoots  map[string]string
+)
+
+// NewLintCommand creates the "lint" subcommand
+func NewLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [paths...]",
+		Short: "Statically checks policy files",
+		Long: "Statically checks the policy files of the test files for rules not referenced by the approval policy, " +
+			"references to undefined rules and invalid regular expressions. Against the team and organization members " +
+			"of the test files it checks for unknown required teams, counts no set of eligible users can reach and rules " +
+			"that can never apply.",
+		RunE: runLint,
+	}
+
+	cmd.Flags().StringVarP(&lintOutputFormat, "output", "o", defaultOutput, "output format (pretty, efm, json)")
+	cmd.Flags().StringVarP(&lintPolicyFile, "policy", "p", defaultPolicyFile, "path to the default policy file, used by test files not declaring a policy")
+	cmd.Flags().StringToStringVar(&lintRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+
+	return cmd
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	tests := &models.TestFile{}
+	if len(args) == 0 {
+		args = []string{defaultTestPath}
+		// Without test files only the checks not needing fixtures are run
+		if _, err := os.Stat(defaultTestPath); errors.Is(err, fs.ErrNotExist) {
+			args = nil
+		}
+	}
+	if len(args) > 0 {
+		var err error
+		if tests, err = loader.LoadTestFiles(args); err != nil {
+			return withExitCode(ExitTestFileError, fmt.Errorf("failed to load tests: %w", err))
+		}
+	}
+
+	// Fixtures of every policy: the contexts of the test cases bound to it, and their default contexts
+	defaultPolicy := filepath.Clean(lintPolicyFile)
+	contexts := map[string][]models.TestContext{}
+	for _, tc := range tests.TestCases {
+		policyFile := tc.PolicyFile
+		if policyFile == "" {
+			policyFile = defaultPolicy
+		}
+		contexts[policyFile] = append(contexts[policyFile], tc.DefaultContextOr(tests.DefaultContext), tc.Context)
+	}
+	if len(contexts) == 0 || cmd.Flags().Changed("policy") {
+		if _, ok := contexts[defaultPolicy]; !ok {
+			contexts[defaultPolicy] = nil
+		}
+	}
+
+	issues := []lint.Issue{}
+	opts := loader.PolicyOptions{RemoteRoots: lintRemoteRoots}
+	for _, policyFile := range slices.Sorted(maps.Keys(contexts)) {
+		fileName, content, err := loader.ReadPolicyFile(policyFile, opts)
+		if err != nil {
+			return withExitCode(ExitConfigError, fmt.Errorf("failed to load policy: %w", err))
+		}
+		var fixtures *lint.Fixtures
+		if len(tests.TestCases) > 0 {
+			fixtures = lint.NewFixtures(contexts[policyFile]...)
+		}
+		issues = append(issues, lint.Lint(fileName, content, fixtures)...)
+	}
+
+	if err := printIssues(issues, lintOutputFormat); err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return withExitCode(ExitFailed, nil)
+	}
+	return nil
+}
+
+// printIssues prints lint issues in the given output format
+func printIssues(issues []lint.Issue, outputFormat string) error {
+	switch outputFormat {
+	case "efm":
+		for _, issue := range issues {
+			log.Printf("%s:%d:%d: %s", issue.File, issue.Line, issue.Column, issue.Message)
+		}
+	case "json":
+		content, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal issues: %w", err)
+		}
+		log.Print(string(content))
+	default:
+		output.PrintLintIssues(issues)
+	}
+	return nil
+}
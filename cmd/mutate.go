@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/mutation"
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mutateVerbose     int
+	mutateFilter      string
+	mutatePolicyFile  string
+	mutateRemoteRoots map[string]string
+)
+
+// NewMutateCommand creates the "mutate" subcommand
+func NewMutateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mutate [paths...]",
+		Short: "Checks whether the test cases detect systematic changes to a policy file",
+		Long: "Applies systematic mutations to a policy file (dropping teams, decrementing counts, loosening path regexes, " +
+			"swapping and/or, removing rules), runs the test cases bound to the policy against every mutant " +
+			"and reports the mutants that no test case detects.",
+		RunE: runMutate,
+	}
+
+	cmd.Flags().CountVarP(&mutateVerbose, "verbose", "v", "also report killed and invalid mutants")
+	cmd.Flags().StringVarP(&mutateFilter, "filter", "f", "", "filter test cases by name using regex")
+	cmd.Flags().StringVarP(&mutatePolicyFile, "policy", "p", defaultPolicyFile, "path to the policy file to mutate")
+	cmd.Flags().StringToStringVar(&mutateRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+
+	return cmd
+}
+
+func runMutate(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		args = []string{defaultTestPath}
+	}
+
+	tests, err := loader.LoadTestFiles(args)
+	if err != nil {
+		return withExitCode(ExitTestFileError, fmt.Errorf("failed to load tests: %w", err))
+	}
+	config, err := loader.LoadPolicyConfig(mutatePolicyFile, loader.PolicyOptions{RemoteRoots: mutateRemoteRoots})
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load policy: %w", err))
+	}
+
+	testCases, err := runner.FilterTestCases(tests.TestCases, mutateFilter)
+	if err != nil {
+		return fmt.Errorf("invalid filter regex: %w", err)
+	}
+	// Only the test cases bound to the mutated policy can detect its mutants
+	var boundCases []models.TestCase
+	for _, tc := range testCases {
+		if tc.PolicyFile == "" || tc.PolicyFile == filepath.Clean(mutatePolicyFile) {
+			boundCases = append(boundCases, tc)
+		}
+	}
+	if len(boundCases) == 0 {
+		return withExitCode(ExitNoTests, fmt.Errorf("no test cases are bound to %s", mutatePolicyFile))
+	}
+
+	survived, err := mutation.Run(config, tests.DefaultContext, boundCases, mutateVerbose)
+	switch {
+	case errors.Is(err, mutation.ErrNothingToRun):
+		return withExitCode(ExitNoTests, err)
+	case errors.Is(err, mutation.ErrFailingSuite):
+		return withExitCode(ExitFailed, err)
+	case err != nil:
+		return withExitCode(ExitConfigError, err)
+	case survived > 0:
+		return withExitCode(ExitFailed, nil)
+	}
+	return nil
+}
@@ -21,9 +21,23 @@ func NewRootCommand() *cobra.Command {
 		Use:   "policy-bot-tests",
 		Short: "A testing tool for policy-bot configurations",
 		Long:  "A testing tool for policy-bot configurations that loads test cases and evaluates them against a policy file.",
+		// Errors are printed by Execute, which knows the ones to exit without a message
+		SilenceErrors: true,
+		// Usage is printed for errors parsing the flags, the ones returned by commands are not about usage
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cmd.SilenceUsage = true
+		},
 	}
 
 	rootCmd.AddCommand(NewVerifyCommand())
+	rootCmd.AddCommand(NewDiffCommand())
+	rootCmd.AddCommand(NewMutateCommand())
+	rootCmd.AddCommand(NewGenerateCommand())
+	rootCmd.AddCommand(NewImportCommand())
+	rootCmd.AddCommand(NewExplainCommand())
+	rootCmd.AddCommand(NewLintCommand())
+	rootCmd.AddCommand(NewCheckCommand())
+	rootCmd.AddCommand(NewSchemaCommand())
 	rootCmd.Version = fmt.Sprintf("%s (commit: %s, date: %s)", Version, Commit, Date)
 
 	return rootCmd
@@ -35,6 +49,9 @@ func Execute() {
 	log.SetOutput(os.Stdout)
 
 	if err := NewRootCommand().Execute(); err != nil {
-		os.Exit(1)
+		if msg := err.Error(); msg != "" {
+			fmt.Fprintln(os.Stderr, "Error:", msg)
+		}
+		os.Exit(exitCode(err))
 	}
 }
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/reegnz/policy-bot-tests/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// NewSchemaCommand creates the "schema" subcommand
+func NewSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Prints the JSON Schema of test files",
+		Long:  "Prints the JSON Schema of test files, for validation and completion in editors.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(schema.TestFile()); err != nil {
+				return fmt.Errorf("failed to encode schema: %w", err)
+			}
+			return nil
+		},
+	}
+}
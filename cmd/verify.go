@@ -1,11 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"time"
 
+	"github.com/reegnz/policy-bot-tests/internal/coverage"
+	"github.com/reegnz/policy-bot-tests/internal/explain"
+	"github.com/reegnz/policy-bot-tests/internal/lint"
 	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/output"
 	"github.com/reegnz/policy-bot-tests/internal/runner"
+	"github.com/reegnz/policy-bot-tests/internal/snapshot"
 	"github.com/spf13/cobra"
 )
 
@@ -18,8 +29,33 @@ const (
 var (
 	verifyVerbose      int
 	verifyFilter       string
+	verifyTags         []string
+	verifyExcludeTags  []string
 	verifyOutputFormat string
 	verifyPolicyFile   string
+	verifyRemoteRoots  map[string]string
+	verifyPolicyRef    string
+	verifyTestsRef     string
+
+	verifyCoverage          bool
+	verifyCoverageFile      string
+	verifyCoverageThreshold float64
+	verifyPredicateCoverage bool
+
+	verifySnapshot        bool
+	verifyUpdateSnapshots bool
+
+	verifyAccept bool
+	verifyStrict bool
+
+	verifyParallel int
+	verifyTimeout  time.Duration
+
+	verifyNoTestsOK bool
+
+	verifyFailFast bool
+	verifyShuffle  string
+	verifyCount    int
 )
 
 // NewVerifyCommand creates the "verify" subcommand
@@ -33,28 +69,215 @@ func NewVerifyCommand() *cobra.Command {
 
 	cmd.Flags().CountVarP(&verifyVerbose, "verbose", "v", "increase verbosity (can be repeated: -v, -vv, -vvv)")
 	cmd.Flags().StringVarP(&verifyFilter, "filter", "f", "", "filter test cases by name using regex")
+	cmd.Flags().StringSliceVar(&verifyTags, "tags", nil, "run only the test cases with any of these tags")
+	cmd.Flags().StringSliceVar(&verifyExcludeTags, "exclude-tags", nil, "don't run the test cases with any of these tags")
 	cmd.Flags().StringVarP(&verifyOutputFormat, "output", "o", defaultOutput, "output format (pretty, efm)")
-	cmd.Flags().StringVarP(&verifyPolicyFile, "policy", "p", defaultPolicyFile, "path to the policy file")
+	cmd.Flags().StringVarP(&verifyPolicyFile, "policy", "p", defaultPolicyFile, "path to the default policy file, used by test files not declaring a policy")
+	cmd.Flags().StringToStringVar(&verifyRemoteRoots, "remote-root", nil, "resolve remote policy references to a local directory (org/repo=path, can be repeated)")
+	cmd.Flags().StringVar(&verifyPolicyRef, "policy-ref", "", "read policy files at this git revision instead of the working tree")
+	cmd.Flags().StringVar(&verifyTestsRef, "tests-ref", "", "read test files at this git revision instead of the working tree")
+	cmd.Flags().BoolVar(&verifyCoverage, "coverage", false, "report which rule statuses were observed across all test cases")
+	cmd.Flags().StringVar(&verifyCoverageFile, "coverage-file", "", "write the rule coverage report to a JSON file (implies --coverage)")
+	cmd.Flags().Float64Var(&verifyCoverageThreshold, "coverage-threshold", 0, "fail if rule coverage is below this percentage (implies --coverage)")
+	cmd.Flags().BoolVar(&verifySnapshot, "snapshot", false, "compare the evaluation tree of every test case with its snapshot, not only of the ones with snapshot: true")
+	cmd.Flags().BoolVar(&verifyUpdateSnapshots, "update-snapshots", false, "rewrite mismatching snapshots instead of failing")
+	cmd.Flags().BoolVar(&verifyAccept, "accept", false, "rewrite the assert blocks of failing test cases from their actual evaluation")
+	cmd.Flags().BoolVar(&verifyStrict, "strict", false, "fail on duplicate test names, assertions on undefined rules and reviews by users not in any team")
+	cmd.Flags().IntVar(&verifyParallel, "parallel", 1, "number of test cases to evaluate concurrently, results are still printed in order")
+	cmd.Flags().DurationVar(&verifyTimeout, "timeout", 0, "fail test cases whose evaluation takes longer, unless they declare their own timeout (0 means no limit)")
+	cmd.Flags().BoolVar(&verifyNoTestsOK, "no-tests-ok", false, "succeed if no test case matches the filter and tags, instead of exiting with code 4")
+	cmd.Flags().BoolVar(&verifyFailFast, "fail-fast", false, "stop after the first test case that doesn't pass")
+	cmd.Flags().StringVar(&verifyShuffle, "shuffle", "off", "run test cases in a random order (off, on, or the seed of a previous run as --shuffle=<seed>)")
+	cmd.Flags().Lookup("shuffle").NoOptDefVal = "on"
+	cmd.Flags().IntVar(&verifyCount, "count", 1, "run every test case this many times")
+	cmd.Flags().BoolVar(&verifyPredicateCoverage, "predicate-coverage", false, "report whether every predicate of the rules' if conditions was observed both satisfied and unsatisfied")
 
 	return cmd
 }
 
 func runVerify(cmd *cobra.Command, args []string) error {
-	evaluator, err := loader.LoadPolicyEvaluator(verifyPolicyFile)
+	if len(args) == 0 {
+		args = []string{defaultTestPath}
+	}
+	if verifyParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	if verifyCount < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+	shuffle, seed, err := parseShuffle(verifyShuffle)
 	if err != nil {
-		return fmt.Errorf("failed to load evaluator: %w", err)
+		return err
+	}
+	if err := checkShuffleSeedArgs(verifyShuffle, args); err != nil {
+		return err
+	}
+	if verifyAccept && verifyTestsRef != "" {
+		return fmt.Errorf("--accept can't rewrite test files read from --tests-ref")
+	}
+	if (verifySnapshot || verifyUpdateSnapshots) && verifyTestsRef != "" {
+		return fmt.Errorf("--snapshot and --update-snapshots can't be used with --tests-ref, snapshots are read from the working tree")
 	}
 
-	if len(args) == 0 {
-		args = []string{defaultTestPath}
+	tests, err := loader.LoadTestFilesAtRef(args, verifyTestsRef)
+	if err != nil {
+		return withExitCode(ExitTestFileError, fmt.Errorf("failed to load tests: %w", err))
+	}
+
+	configs, err := loader.LoadPolicyConfigs(tests, verifyPolicyFile, loader.PolicyOptions{
+		RemoteRoots: verifyRemoteRoots,
+		Ref:         verifyPolicyRef,
+	})
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load evaluator: %w", err))
+	}
+	evaluators, err := loader.ParsePolicyEvaluators(configs)
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("failed to load evaluator: %w", err))
+	}
+
+	var issues []lint.Issue
+	if verifyStrict {
+		issues = lint.CheckTests(tests, configs)
+		if len(issues) > 0 {
+			if err := printIssues(issues, verifyOutputFormat); err != nil {
+				return err
+			}
+		}
+	}
+
+	var observers []runner.Observer
+	var ruleCoverage *coverage.RuleTracker
+	if verifyCoverage || verifyCoverageFile != "" || verifyCoverageThreshold > 0 {
+		ruleCoverage = coverage.NewRuleTracker(configs)
+		observers = append(observers, ruleCoverage)
+	}
+	var predicateCoverage *coverage.PredicateTracker
+	if verifyPredicateCoverage {
+		predicateCoverage = coverage.NewPredicateTracker(configs)
+		observers = append(observers, predicateCoverage)
 	}
 
-	tests, err := loader.LoadTestFiles(args)
+	var acceptor *runner.Acceptor
+	if verifyAccept {
+		acceptor = runner.NewAcceptor()
+		observers = append(observers, acceptor)
+	}
+
+	snapshots := snapshot.NewStore(verifySnapshot, verifyUpdateSnapshots)
+
+	// Ctrl-C stops the run, the summary still covers the test cases that were run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	passed, err := runner.RunTests(ctx, evaluators, tests, runner.Options{
+		Verbosity:    verifyVerbose,
+		Filter:       verifyFilter,
+		Tags:         verifyTags,
+		ExcludeTags:  verifyExcludeTags,
+		OutputFormat: verifyOutputFormat,
+		Observers:    observers,
+		Checkers:     []runner.Checker{snapshots},
+		Advisors:     []runner.Advisor{explain.NewAdvisor(configs, evaluators)},
+		Parallel:     verifyParallel,
+		Timeout:      verifyTimeout,
+		FailFast:     verifyFailFast,
+		Shuffle:      shuffle,
+		Seed:         seed,
+		Count:        verifyCount,
+	})
+	if errors.Is(err, runner.ErrNoTestsMatched) {
+		if !verifyNoTestsOK {
+			return withExitCode(ExitNoTests, err)
+		}
+		if verifyOutputFormat == "pretty" {
+			log.Printf("Nothing to run, %v", err)
+		}
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to load tests: %w", err)
+		return withExitCode(ExitConfigError, err)
+	}
+
+	if err := snapshots.Save(); err != nil {
+		log.Printf("Failed to save snapshots: %v", err)
+		passed = false
+	} else if len(snapshots.Written) > 0 && verifyOutputFormat == "pretty" {
+		log.Printf("Wrote %d snapshot(s).", len(snapshots.Written))
+	}
+
+	if acceptor != nil {
+		if err := acceptor.Save(); err != nil {
+			log.Printf("Failed to accept assertions: %v", err)
+			passed = false
+		} else if len(acceptor.Accepted) > 0 && verifyOutputFormat == "pretty" {
+			log.Printf("Accepted the actual results of %d test case(s), rerun to verify.", len(acceptor.Accepted))
+		}
+	}
+
+	if ruleCoverage != nil {
+		if !reportRuleCoverage(ruleCoverage) {
+			passed = false
+		}
+	}
+	if predicateCoverage != nil && verifyOutputFormat == "pretty" {
+		output.PrintPredicateCoverage(predicateCoverage.Reports)
 	}
-	if passed := runner.RunTests(evaluator, tests, verifyVerbose, verifyFilter, verifyOutputFormat); !passed {
-		os.Exit(1)
+	if len(issues) > 0 {
+		return withExitCode(ExitTestFileError, nil)
+	}
+	if !passed {
+		return withExitCode(ExitFailed, nil)
 	}
 	return nil
 }
+
+// parseShuffle parses the value of the --shuffle flag into whether to shuffle and the seed to use
+func parseShuffle(value string) (shuffle bool, seed int64, err error) {
+	switch value {
+	case "off":
+		return false, 0, nil
+	case "on":
+		return true, time.Now().UnixNano(), nil
+	}
+	seed, err = strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid --shuffle value %q, expected off, on or a seed", value)
+	}
+	return true, seed, nil
+}
+
+// checkShuffleSeedArgs rejects a seed passed as --shuffle <seed>, the flag only takes
+// its value in the --shuffle=<seed> form, so the seed would be read as a test path
+func checkShuffleSeedArgs(value string, args []string) error {
+	if value != "on" {
+		return nil
+	}
+	for _, arg := range args {
+		if _, err := strconv.ParseInt(arg, 10, 64); err != nil {
+			continue
+		}
+		if _, err := os.Stat(arg); err != nil {
+			return fmt.Errorf("test path %s doesn't exist, use --shuffle=%s to shuffle with a seed", arg, arg)
+		}
+	}
+	return nil
+}
+
+// reportRuleCoverage prints and writes the rule coverage report.
+// It returns false if the coverage is below the configured threshold.
+func reportRuleCoverage(ruleCoverage *coverage.RuleTracker) bool {
+	if verifyOutputFormat == "pretty" {
+		output.PrintRuleCoverage(ruleCoverage.Reports)
+	}
+	if verifyCoverageFile != "" {
+		if err := ruleCoverage.WriteJSON(verifyCoverageFile); err != nil {
+			log.Printf("Failed to write coverage report: %v", err)
+			return false
+		}
+	}
+	if percent := ruleCoverage.Percent(); percent < verifyCoverageThreshold {
+		log.Printf("Rule coverage %.1f%% is below the threshold of %.1f%%", percent, verifyCoverageThreshold)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package coverage
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/policy/predicate"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// PredicateCoverage holds the outcomes observed for a single predicate of a rule
+type PredicateCoverage struct {
+	Rule        string
+	Predicate   string
+	Satisfied   int
+	Unsatisfied int
+	Errors      int
+
+	predicate predicate.Predicate
+}
+
+// Covered returns the number of observed outcomes, out of satisfied and unsatisfied
+func (pc *PredicateCoverage) Covered() int {
+	covered := 0
+	if pc.Satisfied > 0 {
+		covered++
+	}
+	if pc.Unsatisfied > 0 {
+		covered++
+	}
+	return covered
+}
+
+// PredicateReport tracks predicate coverage for a single policy
+type PredicateReport struct {
+	PolicyFile string
+	Predicates []*PredicateCoverage
+}
+
+// NewPredicateReport creates a report tracking every predicate in the if
+// conditions of the rules defined by the policy configuration
+func NewPredicateReport(policyFile string, config *policy.Config) *PredicateReport {
+	r := &PredicateReport{PolicyFile: policyFile}
+	for _, rule := range config.ApprovalRules {
+		r.addPredicates(rule.Name, rule.Predicates)
+	}
+	if d := config.Policy.Disapproval; d != nil {
+		r.addPredicates(DisapprovalRule, d.Predicates)
+	}
+	return r
+}
+
+func (r *PredicateReport) addPredicates(rule string, predicates predicate.Predicates) {
+	for _, np := range NamedPredicates(predicates) {
+		r.Predicates = append(r.Predicates, &PredicateCoverage{
+			Rule:      rule,
+			Predicate: np.Name,
+			predicate: np.Predicate,
+		})
+	}
+}
+
+// Record evaluates every tracked predicate against the pull request context.
+// Predicates are evaluated independently of each other, so predicates that
+// policy-bot wouldn't reach because an earlier one failed are still covered.
+func (r *PredicateReport) Record(prctx pull.Context) {
+	for _, pc := range r.Predicates {
+		result, err := pc.predicate.Evaluate(context.Background(), prctx)
+		switch {
+		case err != nil:
+			pc.Errors++
+		case result.Satisfied:
+			pc.Satisfied++
+		default:
+			pc.Unsatisfied++
+		}
+	}
+}
+
+// Covered returns the number of observed and the number of possible predicate outcomes
+func (r *PredicateReport) Covered() (covered, total int) {
+	for _, pc := range r.Predicates {
+		total += 2
+		covered += pc.Covered()
+	}
+	return
+}
+
+// Percent returns the percentage of possible predicate outcomes that were observed
+func (r *PredicateReport) Percent() float64 {
+	return percent(r.Covered())
+}
+
+// PredicateTracker collects predicate coverage across all policies of a test run
+type PredicateTracker struct {
+	Reports  []*PredicateReport
+	byPolicy map[string]*PredicateReport
+}
+
+// NewPredicateTracker creates a tracker for the given policy configurations, keyed by policy file
+func NewPredicateTracker(configs map[string]*policy.Config) *PredicateTracker {
+	t := &PredicateTracker{byPolicy: map[string]*PredicateReport{}}
+	for _, policyFile := range sortedPolicyFiles(configs) {
+		report := NewPredicateReport(policyFile, configs[policyFile])
+		t.Reports = append(t.Reports, report)
+		t.byPolicy[policyFile] = report
+	}
+	return t
+}
+
+// Observe evaluates the predicates of the test case's policy against its context
+func (t *PredicateTracker) Observe(tc models.TestCase, mergedContext models.TestContext, _ *common.Result) {
+	if report, ok := t.byPolicy[tc.PolicyFile]; ok {
+		report.Record(models.NewGitHubContext(mergedContext))
+	}
+}
+
+// NamedPredicate is a predicate with the key it is configured with
+type NamedPredicate struct {
+	Name      string
+	Predicate predicate.Predicate
+}
+
+// NamedPredicates returns the configured predicates, named by their YAML keys
+func NamedPredicates(predicates predicate.Predicates) []NamedPredicate {
+	var named []NamedPredicate
+	v := reflect.ValueOf(predicates)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Pointer || field.IsNil() {
+			continue
+		}
+		p, ok := field.Interface().(predicate.Predicate)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		named = append(named, NamedPredicate{Name: name, Predicate: p})
+	}
+	return named
+}
@@ -0,0 +1,235 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// DisapprovalRule is the name under which the disapproval policy is tracked
+const DisapprovalRule = "disapproval"
+
+// Statuses lists the evaluation statuses tracked for every rule, in report order
+var Statuses = []common.EvaluationStatus{
+	common.StatusApproved,
+	common.StatusPending,
+	common.StatusSkipped,
+	common.StatusDisapproved,
+}
+
+// RuleCoverage holds the evaluation statuses observed for a single rule
+type RuleCoverage struct {
+	Name string
+	// Possible lists the statuses the rule can reach given its definition
+	Possible []common.EvaluationStatus
+	// Seen counts how many test cases evaluated the rule to each status
+	Seen map[common.EvaluationStatus]int
+}
+
+// IsPossible returns true if the rule can reach the status
+func (rc *RuleCoverage) IsPossible(status common.EvaluationStatus) bool {
+	return slices.Contains(rc.Possible, status)
+}
+
+// Missing returns the possible statuses that were never observed
+func (rc *RuleCoverage) Missing() []common.EvaluationStatus {
+	var missing []common.EvaluationStatus
+	for _, status := range rc.Possible {
+		if rc.Seen[status] == 0 {
+			missing = append(missing, status)
+		}
+	}
+	return missing
+}
+
+// RuleReport tracks rule coverage for a single policy
+type RuleReport struct {
+	PolicyFile string
+	Rules      []*RuleCoverage
+	byName     map[string]*RuleCoverage
+}
+
+// NewRuleReport creates a report tracking every rule defined by the policy configuration
+func NewRuleReport(policyFile string, config *policy.Config) *RuleReport {
+	r := &RuleReport{
+		PolicyFile: policyFile,
+		byName:     map[string]*RuleCoverage{},
+	}
+	for _, rule := range config.ApprovalRules {
+		possible := []common.EvaluationStatus{common.StatusApproved}
+		if rule.Requires.Count > 0 || len(rule.Requires.Conditions.Predicates()) > 0 {
+			possible = append(possible, common.StatusPending)
+		}
+		if len(rule.Predicates.Predicates()) > 0 {
+			possible = append(possible, common.StatusSkipped)
+		}
+		r.addRule(rule.Name, possible)
+	}
+	if d := config.Policy.Disapproval; d != nil {
+		possible := []common.EvaluationStatus{common.StatusSkipped}
+		if len(d.Predicates.Predicates()) > 0 || !d.Requires.IsZero() {
+			possible = append(possible, common.StatusDisapproved)
+		}
+		r.addRule(DisapprovalRule, possible)
+	}
+	return r
+}
+
+func (r *RuleReport) addRule(name string, possible []common.EvaluationStatus) {
+	if _, ok := r.byName[name]; ok {
+		return
+	}
+	rc := &RuleCoverage{
+		Name:     name,
+		Possible: possible,
+		Seen:     map[common.EvaluationStatus]int{},
+	}
+	r.Rules = append(r.Rules, rc)
+	r.byName[name] = rc
+}
+
+// Record records the rule statuses of an evaluation result tree
+func (r *RuleReport) Record(result *common.Result) {
+	// The root of the tree holds the approval and the disapproval results
+	for _, child := range result.Children {
+		if child.Name == DisapprovalRule && len(child.Children) == 0 {
+			r.recordRule(DisapprovalRule, child.Status)
+			continue
+		}
+		r.recordApproval(child)
+	}
+}
+
+// recordApproval recursively records the statuses of the rules in an approval tree
+func (r *RuleReport) recordApproval(result *common.Result) {
+	if len(result.Children) > 0 {
+		for _, child := range result.Children {
+			r.recordApproval(child)
+		}
+		return
+	}
+	r.recordRule(result.Name, result.Status)
+}
+
+func (r *RuleReport) recordRule(name string, status common.EvaluationStatus) {
+	if rc, ok := r.byName[name]; ok {
+		rc.Seen[status]++
+	}
+}
+
+// Covered returns the number of observed and the number of possible rule statuses
+func (r *RuleReport) Covered() (covered, total int) {
+	for _, rc := range r.Rules {
+		total += len(rc.Possible)
+		covered += len(rc.Possible) - len(rc.Missing())
+	}
+	return
+}
+
+// Percent returns the percentage of possible rule statuses that were observed
+func (r *RuleReport) Percent() float64 {
+	return percent(r.Covered())
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(covered) * 100 / float64(total)
+}
+
+// sortedPolicyFiles returns the policy files of the configurations in sorted order
+func sortedPolicyFiles(configs map[string]*policy.Config) []string {
+	var policyFiles []string
+	for policyFile := range configs {
+		policyFiles = append(policyFiles, policyFile)
+	}
+	slices.Sort(policyFiles)
+	return policyFiles
+}
+
+// RuleTracker collects rule coverage across all policies of a test run
+type RuleTracker struct {
+	Reports  []*RuleReport
+	byPolicy map[string]*RuleReport
+}
+
+// NewRuleTracker creates a tracker for the given policy configurations, keyed by policy file
+func NewRuleTracker(configs map[string]*policy.Config) *RuleTracker {
+	t := &RuleTracker{byPolicy: map[string]*RuleReport{}}
+	for _, policyFile := range sortedPolicyFiles(configs) {
+		report := NewRuleReport(policyFile, configs[policyFile])
+		t.Reports = append(t.Reports, report)
+		t.byPolicy[policyFile] = report
+	}
+	return t
+}
+
+// Observe records the evaluation result of a test case against its policy
+func (t *RuleTracker) Observe(tc models.TestCase, _ models.TestContext, result *common.Result) {
+	if report, ok := t.byPolicy[tc.PolicyFile]; ok {
+		report.Record(result)
+	}
+}
+
+// Percent returns the percentage of possible rule statuses observed across all policies
+func (t *RuleTracker) Percent() float64 {
+	var covered, total int
+	for _, report := range t.Reports {
+		c, tt := report.Covered()
+		covered += c
+		total += tt
+	}
+	return percent(covered, total)
+}
+
+type jsonReport struct {
+	Coverage float64      `json:"coverage"`
+	Policies []jsonPolicy `json:"policies"`
+}
+
+type jsonPolicy struct {
+	PolicyFile string     `json:"policy_file"`
+	Coverage   float64    `json:"coverage"`
+	Rules      []jsonRule `json:"rules"`
+}
+
+type jsonRule struct {
+	Name     string         `json:"name"`
+	Statuses map[string]int `json:"statuses"`
+	Missing  []string       `json:"missing"`
+}
+
+// WriteJSON writes the coverage of all policies to a JSON file.
+// Only the statuses a rule can reach are listed for it.
+func (t *RuleTracker) WriteJSON(fileName string) error {
+	out := jsonReport{Coverage: t.Percent(), Policies: []jsonPolicy{}}
+	for _, report := range t.Reports {
+		p := jsonPolicy{PolicyFile: report.PolicyFile, Coverage: report.Percent(), Rules: []jsonRule{}}
+		for _, rc := range report.Rules {
+			rule := jsonRule{Name: rc.Name, Statuses: map[string]int{}, Missing: []string{}}
+			for _, status := range rc.Possible {
+				rule.Statuses[status.String()] = rc.Seen[status]
+			}
+			for _, status := range rc.Missing() {
+				rule.Missing = append(rule.Missing, status.String())
+			}
+			p.Rules = append(p.Rules, rule)
+		}
+		out.Policies = append(out.Policies, p)
+	}
+
+	content, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal coverage: %w", err)
+	}
+	if err := os.WriteFile(fileName, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", fileName, err)
+	}
+	return nil
+}
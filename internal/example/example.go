@@ -0,0 +1,95 @@
+package example
+
+import (
+	"regexp/syntax"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy/common"
+)
+
+// ForRegexp returns a string matching the regular expression
+func ForRegexp(re common.Regexp) (string, bool) {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var sb strings.Builder
+	writeExample(&sb, parsed.Simplify())
+	example := sb.String()
+	return example, re.Matches(example)
+}
+
+// writeExample writes a string matching the syntax tree. Repetitions are
+// expanded at least once to avoid degenerate examples like "dir/".
+func writeExample(sb *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 {
+			sb.WriteRune(preferredRune(re.Rune))
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteString("x")
+	case syntax.OpCapture:
+		writeExample(sb, re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		writeExample(sb, re.Sub[0])
+	case syntax.OpRepeat:
+		for i := 0; i < max(re.Min, 1); i++ {
+			writeExample(sb, re.Sub[0])
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeExample(sb, sub)
+		}
+	case syntax.OpAlternate:
+		writeExample(sb, re.Sub[0])
+	}
+}
+
+// preferredRune picks a readable rune from character class ranges
+func preferredRune(ranges []rune) rune {
+	for _, r := range []rune{'x', 'a', '0'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r
+			}
+		}
+	}
+	return ranges[0]
+}
+
+// ForGlob returns a path matching the glob pattern
+func ForGlob(glob common.Glob) (string, bool) {
+	example := strings.NewReplacer("**", "dir", "*", "file", "?", "x").Replace(string(glob))
+	return example, glob.Matches(example)
+}
+
+// Path returns a path matching the first usable regex or glob
+func Path(paths []common.Regexp, globs []common.Glob) (string, bool) {
+	for _, re := range paths {
+		if example, ok := ForRegexp(re); ok {
+			return example, true
+		}
+	}
+	for _, glob := range globs {
+		if example, ok := ForGlob(glob); ok {
+			return example, true
+		}
+	}
+	return "", false
+}
+
+// ApprovalComment returns a comment body approving with the given methods
+func ApprovalComment(methods *common.Methods) (string, bool) {
+	if comments := methods.GetComments(); len(comments) > 0 {
+		return comments[0], true
+	}
+	for _, pattern := range methods.GetCommentPatterns() {
+		if example, ok := ForRegexp(pattern); ok {
+			return example, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,233 @@
+package explain
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/approval"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/reegnz/policy-bot-tests/internal/coverage"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// Explanation explains the evaluation of a test case
+type Explanation struct {
+	Result common.Result
+	Rules  []*Rule
+}
+
+// Rule explains the evaluation of a single approval rule
+type Rule struct {
+	Name              string
+	Status            common.EvaluationStatus
+	StatusDescription string
+	// Applied is false for rules the approval policy doesn't reference
+	Applied bool
+	// SkippedBy describes the if predicates that were not satisfied
+	SkippedBy []string
+	// UnsatisfiedConditions describes the required conditions that were not satisfied
+	UnsatisfiedConditions []string
+	// Approvals lists the reviews and comments considered for the rule
+	Approvals []Approval
+	// Suggestion is the minimal context change approving a pending rule
+	Suggestion *Suggestion
+}
+
+// Approval is a review or comment considered as an approval of a rule
+type Approval struct {
+	// Kind is either "review" or "comment"
+	Kind   string
+	Author string
+	// Detail is the state of a review or the body of a comment
+	Detail  string
+	Counted bool
+	// Reason explains why the approval was not counted
+	Reason string
+}
+
+// Explain evaluates the merged context of a test case and explains the result of every approval rule.
+// The configuration must have been parsed into the evaluator, so the rules have their default options.
+func Explain(config *policy.Config, evaluator common.Evaluator, merged models.TestContext) *Explanation {
+	ctx := context.Background()
+	prctx := models.NewGitHubContext(merged)
+	e := &Explanation{Result: evaluator.Evaluate(ctx, prctx)}
+	for _, rule := range config.ApprovalRules {
+		e.Rules = append(e.Rules, explainRule(ctx, evaluator, prctx, merged, rule, findRule(&e.Result, rule.Name)))
+	}
+	return e
+}
+
+// findRule returns the result of a rule in the evaluation tree, or nil if it wasn't evaluated
+func findRule(result *common.Result, name string) *common.Result {
+	if len(result.Children) == 0 {
+		if result.Name == name {
+			return result
+		}
+		return nil
+	}
+	for _, child := range result.Children {
+		if found := findRule(child, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func explainRule(ctx context.Context, evaluator common.Evaluator, prctx pull.Context, merged models.TestContext, rule *approval.Rule, result *common.Result) *Rule {
+	r := &Rule{Name: rule.Name}
+	if result == nil {
+		return r
+	}
+	r.Applied = true
+	r.Status = result.Status
+	r.StatusDescription = result.StatusDescription
+
+	for _, np := range coverage.NamedPredicates(rule.Predicates) {
+		pr, err := np.Predicate.Evaluate(ctx, prctx)
+		switch {
+		case err != nil:
+			r.SkippedBy = append(r.SkippedBy, fmt.Sprintf("%s: %v", np.Name, err))
+		case !pr.Satisfied:
+			r.SkippedBy = append(r.SkippedBy, fmt.Sprintf("%s: %s", np.Name, DescribePredicate(pr)))
+		}
+	}
+	if r.Status == common.StatusSkipped {
+		return r
+	}
+
+	for _, condition := range result.Requires.Conditions {
+		if !condition.Satisfied {
+			r.UnsatisfiedConditions = append(r.UnsatisfiedConditions, DescribePredicate(condition))
+		}
+	}
+	if rule.Requires.Count > 0 {
+		r.Approvals = explainApprovals(ctx, prctx, merged, rule, result)
+	}
+	if r.Status == common.StatusPending {
+		r.Suggestion = Suggest(ctx, evaluator, prctx, merged, rule, result)
+	}
+	return r
+}
+
+// explainApprovals classifies the reviews and comments of the context as counted or ignored by the rule
+func explainApprovals(ctx context.Context, prctx pull.Context, merged models.TestContext, rule *approval.Rule, result *common.Result) []Approval {
+	methods := rule.Options.GetMethods()
+	approvers := map[string]bool{}
+	for _, c := range result.Requires.Approvers {
+		approvers[c.User] = true
+	}
+
+	var approvals []Approval
+	for _, review := range merged.Reviews {
+		a := Approval{Kind: "review", Author: review.Author, Detail: string(review.State)}
+		switch {
+		case !methods.IsGithubReview() && len(methods.GetGithubReviewCommentPatterns()) == 0:
+			a.Reason = "wrong method, the rule doesn't accept reviews"
+		case pull.ReviewState(review.State) != methods.GithubReviewState:
+			a.Reason = fmt.Sprintf("review state is %s, the rule requires %s", review.State, methods.GithubReviewState)
+		case len(methods.GetGithubReviewCommentPatterns()) > 0:
+			a.Reason = "the rule requires review bodies matching github_review_comment_patterns, which reviews in the test context don't have"
+		default:
+			a.Reason = actorReason(ctx, prctx, merged, rule, review.Author)
+		}
+		approvals = append(approvals, a)
+	}
+	for _, comment := range merged.Comments {
+		a := Approval{Kind: "comment", Author: comment.Author, Detail: comment.Body}
+		switch {
+		case len(methods.GetComments()) == 0 && len(methods.GetCommentPatterns()) == 0:
+			a.Reason = "wrong method, the rule doesn't accept comments"
+		case !methods.CommentMatches(comment.Body):
+			a.Reason = "the comment doesn't match the approval comments or comment patterns of the rule"
+		default:
+			a.Reason = actorReason(ctx, prctx, merged, rule, comment.Author)
+		}
+		approvals = append(approvals, a)
+	}
+
+	for i := range approvals {
+		if approvals[i].Reason == "" {
+			approvals[i].Counted = approvers[approvals[i].Author]
+			if !approvals[i].Counted {
+				approvals[i].Reason = "an earlier approval by the same user was counted"
+			}
+		}
+	}
+	return approvals
+}
+
+// actorReason explains why an approval by the user doesn't count, or returns an empty string if it does
+func actorReason(ctx context.Context, prctx pull.Context, merged models.TestContext, rule *approval.Rule, user string) string {
+	if isBanned(merged, rule, user) {
+		return "the author of the pull request can't approve, allow_author is not set"
+	}
+	isActor, err := rule.Requires.Actors.IsActor(ctx, prctx, user)
+	if err != nil {
+		return fmt.Sprintf("failed to check membership: %v", err)
+	}
+	if !isActor {
+		return fmt.Sprintf("not in %s", DescribeActors(rule.Requires.Actors))
+	}
+	return ""
+}
+
+// isBanned returns true if the rule options prevent the user from approving
+func isBanned(merged models.TestContext, rule *approval.Rule, user string) bool {
+	return user == merged.Author && !rule.Options.IsAllowAuthor() && !rule.Options.IsAllowContributor()
+}
+
+// DescribeActors describes the users, teams, organizations and permissions allowed to approve
+func DescribeActors(actors common.Actors) string {
+	var parts []string
+	if len(actors.Users) > 0 {
+		parts = append(parts, "users "+strings.Join(actors.Users, ", "))
+	}
+	if len(actors.Teams) > 0 {
+		parts = append(parts, "teams "+strings.Join(actors.Teams, ", "))
+	}
+	if len(actors.Organizations) > 0 {
+		parts = append(parts, "organizations "+strings.Join(actors.Organizations, ", "))
+	}
+	if permissions := actors.GetPermissions(); len(permissions) > 0 {
+		var names []string
+		for _, p := range permissions {
+			names = append(names, p.String())
+		}
+		parts = append(parts, "collaborators with "+strings.Join(names, ", ")+" permission")
+	}
+	if len(parts) == 0 {
+		return "the required actors"
+	}
+	return strings.Join(parts, " or ")
+}
+
+// DescribePredicate describes a predicate result in a sentence
+func DescribePredicate(pr *common.PredicateResult) string {
+	if pr.Description != "" {
+		return pr.Description
+	}
+	values := "none"
+	if len(pr.Values) > 0 {
+		values = strings.Join(pr.Values, ", ")
+	}
+	phrase := pr.ConditionPhrase
+	if pr.Satisfied == pr.ReverseSkipPhrase {
+		phrase = "do not " + phrase
+	}
+	conditions := strings.Join(pr.ConditionValues, ", ")
+	if len(pr.ConditionsMap) > 0 {
+		var parts []string
+		for _, key := range slices.Sorted(maps.Keys(pr.ConditionsMap)) {
+			if len(pr.ConditionsMap[key]) > 0 {
+				parts = append(parts, fmt.Sprintf("%s %s", key, strings.Join(pr.ConditionsMap[key], ", ")))
+			}
+		}
+		conditions = strings.Join(parts, " ")
+	}
+	return strings.TrimSpace(fmt.Sprintf("the %s (%s) %s %s", pr.ValuePhrase, values, phrase, conditions))
+}
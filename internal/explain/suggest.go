@@ -0,0 +1,198 @@
+package explain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/approval"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/reegnz/policy-bot-tests/internal/example"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// Suggestion is a change of the test context approving a pending rule
+type Suggestion struct {
+	Rule        string
+	Description string
+	Reviews     []models.TestReview
+	Comments    []models.TestComment
+	// Verified is true if re-evaluating the changed context approves the rule
+	Verified bool
+}
+
+// Apply returns a copy of the context with the suggested reviews and comments added
+func (s *Suggestion) Apply(tc models.TestContext) models.TestContext {
+	tc.Reviews = append(slices.Clone(tc.Reviews), s.Reviews...)
+	tc.Comments = append(slices.Clone(tc.Comments), s.Comments...)
+	return tc
+}
+
+// actorGroup holds the users of the context eligible to approve as one kind of actor
+type actorGroup struct {
+	Name  string
+	Users []string
+}
+
+// Suggest computes the approvals missing for a pending rule from the users of the
+// membership context allowed to approve, and verifies them by re-evaluating the changed context.
+// It returns nil if the rule isn't missing anything the suggestion could describe.
+func Suggest(ctx context.Context, evaluator common.Evaluator, prctx pull.Context, merged models.TestContext, rule *approval.Rule, result *common.Result) *Suggestion {
+	s := &Suggestion{Rule: rule.Name}
+	approvers := map[string]bool{}
+	for _, c := range result.Requires.Approvers {
+		approvers[c.User] = true
+	}
+
+	var needs []string
+	feasible := true
+	if missing := rule.Requires.Count - len(approvers); missing > 0 {
+		groups := eligibleGroups(ctx, prctx, merged, rule, approvers)
+		var users []string
+		for _, group := range groups {
+			users = append(users, group.Users...)
+		}
+		slices.Sort(users)
+		users = slices.Compact(users)
+
+		methods := rule.Options.GetMethods()
+		body, hasComment := example.ApprovalComment(methods)
+		approvals := "an approval"
+		if missing > 1 {
+			approvals = fmt.Sprintf("%d approvals", missing)
+		}
+		switch {
+		case len(users) < missing:
+			feasible = false
+			needs = append(needs, fmt.Sprintf("%s from %s, but the context only has %d eligible user(s) who haven't approved",
+				approvals, DescribeActors(rule.Requires.Actors), len(users)))
+		case methods.IsGithubReview() && len(methods.GetGithubReviewCommentPatterns()) == 0:
+			for _, user := range users[:missing] {
+				s.Reviews = append(s.Reviews, models.TestReview{Author: user, State: models.ReviewState(methods.GithubReviewState)})
+			}
+			needs = append(needs, fmt.Sprintf("%s from any of %s", approvals, describeGroups(groups)))
+		case hasComment:
+			for _, user := range users[:missing] {
+				s.Comments = append(s.Comments, models.TestComment{Author: user, Body: body})
+			}
+			needs = append(needs, fmt.Sprintf("%s by a %q comment from any of %s", approvals, body, describeGroups(groups)))
+		default:
+			feasible = false
+			needs = append(needs, fmt.Sprintf("%s from any of %s, but no approval method of the rule can be expressed in the test context",
+				approvals, describeGroups(groups)))
+		}
+	}
+	for _, condition := range result.Requires.Conditions {
+		if !condition.Satisfied {
+			feasible = false
+			needs = append(needs, "the required condition: "+DescribePredicate(condition))
+		}
+	}
+	if len(needs) == 0 {
+		return nil
+	}
+
+	if feasible {
+		s.Description = fmt.Sprintf("%s would satisfy %s", strings.Join(needs, " and "), rule.Name)
+		changed := evaluator.Evaluate(ctx, models.NewGitHubContext(s.Apply(merged)))
+		if r := findRule(&changed, rule.Name); r != nil && r.Status == common.StatusApproved {
+			s.Verified = true
+		}
+	} else {
+		s.Description = fmt.Sprintf("%s needs %s", rule.Name, strings.Join(needs, " and "))
+	}
+	return s
+}
+
+// eligibleGroups returns the users of the membership context who can still approve the rule, grouped by actor
+func eligibleGroups(ctx context.Context, prctx pull.Context, merged models.TestContext, rule *approval.Rule, approvers map[string]bool) []actorGroup {
+	actors := rule.Requires.Actors
+	var groups []actorGroup
+	add := func(name string, users []string) {
+		var eligible []string
+		for _, user := range users {
+			if approvers[user] || isBanned(merged, rule, user) {
+				continue
+			}
+			if ok, err := actors.IsActor(ctx, prctx, user); err == nil && ok {
+				eligible = append(eligible, user)
+			}
+		}
+		slices.Sort(eligible)
+		if eligible = slices.Compact(eligible); len(eligible) > 0 {
+			groups = append(groups, actorGroup{Name: name, Users: eligible})
+		}
+	}
+
+	if len(actors.Users) > 0 {
+		add("users", actors.Users)
+	}
+	for _, team := range actors.Teams {
+		members, err := prctx.TeamMembers(team)
+		if err == nil {
+			add(team, members)
+		}
+	}
+	for _, org := range actors.Organizations {
+		members, err := prctx.OrganizationMembers(org)
+		if err == nil {
+			add(org, members)
+		}
+	}
+	if permissions := actors.GetPermissions(); len(permissions) > 0 {
+		collaborators, err := prctx.RepositoryCollaborators(permissions[0])
+		if err == nil {
+			var names []string
+			for _, c := range collaborators {
+				names = append(names, c.Name)
+			}
+			add("collaborators", names)
+		}
+	}
+	return groups
+}
+
+// describeGroups lists the eligible users of every actor group
+func describeGroups(groups []actorGroup) string {
+	var parts []string
+	for _, group := range groups {
+		parts = append(parts, fmt.Sprintf("%s (%s)", group.Name, strings.Join(group.Users, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Advisor suggests verified fixes for the pending rules of failing test cases.
+// It implements runner.Advisor.
+type Advisor struct {
+	configs    map[string]*policy.Config
+	evaluators map[string]common.Evaluator
+}
+
+// NewAdvisor creates an advisor for the policies the configurations were parsed into, keyed by policy file
+func NewAdvisor(configs map[string]*policy.Config, evaluators map[string]common.Evaluator) *Advisor {
+	return &Advisor{configs: configs, evaluators: evaluators}
+}
+
+// Advise returns the verified suggestions approving the pending rules of a pending test case
+func (a *Advisor) Advise(tc models.TestCase, mergedContext models.TestContext, result *common.Result) []string {
+	config, evaluator := a.configs[tc.PolicyFile], a.evaluators[tc.PolicyFile]
+	if result.Status != common.StatusPending || config == nil || evaluator == nil {
+		return nil
+	}
+	ctx := context.Background()
+	prctx := models.NewGitHubContext(mergedContext)
+	var suggestions []string
+	for _, rule := range config.ApprovalRules {
+		ruleResult := findRule(result, rule.Name)
+		if ruleResult == nil || ruleResult.Status != common.StatusPending {
+			continue
+		}
+		if s := Suggest(ctx, evaluator, prctx, mergedContext, rule, ruleResult); s != nil && s.Verified {
+			suggestions = append(suggestions, s.Description)
+		}
+	}
+	return suggestions
+}
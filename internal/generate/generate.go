@@ -0,0 +1,259 @@
+package generate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/approval"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/example"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+)
+
+const (
+	defaultOwner      = "example"
+	defaultRepo       = "example"
+	defaultAuthor     = "pr-author"
+	defaultBaseBranch = "main"
+	defaultHeadBranch = "feature/changes"
+
+	// collaboratorsTeam holds users for rules that only require permissions,
+	// since team members are write collaborators in the test context
+	collaboratorsTeam = "collaborators"
+)
+
+// Options controls the generated test file
+type Options struct {
+	// Owner and Repo of the default context. The owner defaults to the
+	// organization of the first team found in the policy.
+	Owner string
+	Repo  string
+}
+
+// Generate creates a test file with an approved and a pending test case for every approval rule.
+// Assertions are filled in from evaluating the generated test cases against the policy.
+func Generate(config *policy.Config, opts Options) (*models.TestFile, error) {
+	evaluator, err := policy.ParsePolicy(config, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+
+	f := newFixtures(config, opts)
+	tests := &models.TestFile{DefaultContext: f.defaultContext()}
+	// rules holds the rule each test case is generated for
+	var rules []string
+	for _, rule := range config.ApprovalRules {
+		context := f.ruleContext(rule)
+
+		approved := models.TestCase{
+			Name:    fmt.Sprintf("%s is approved", rule.Name),
+			Context: context,
+		}
+		approved.Context.Reviews, approved.Context.Comments = f.approvals(rule)
+		tests.TestCases = append(tests.TestCases, approved)
+		rules = append(rules, rule.Name)
+
+		if rule.Requires.Count > 0 {
+			tests.TestCases = append(tests.TestCases, models.TestCase{
+				Name:    fmt.Sprintf("%s is pending without approvals", rule.Name),
+				Context: context,
+			})
+			rules = append(rules, rule.Name)
+		}
+	}
+
+	for i := range tests.TestCases {
+		tc := &tests.TestCases[i]
+		_, result := runner.EvaluateTestCase(evaluator, f.defaultContext(), models.TestCase{Context: models.NewTestContext(tc.Context)})
+		tc.Assert = ruleAssertion(rules[i], &result)
+	}
+	return tests, nil
+}
+
+// ruleAssertion asserts the evaluation status and the status of a single rule
+func ruleAssertion(rule string, result *common.Result) models.TestAssertion {
+	assert := models.TestAssertion{EvaluationStatus: models.EvaluationStatus(result.Status.String())}
+	switch runner.RuleStatuses(result)[rule] {
+	case common.StatusApproved.String():
+		assert.MustBeApproved = []string{rule}
+	case common.StatusPending.String():
+		assert.MustBePending = []string{rule}
+	case common.StatusSkipped.String():
+		assert.MustBeSkipped = []string{rule}
+	}
+	return assert
+}
+
+// fixtures holds the users and memberships shared by all generated test cases
+type fixtures struct {
+	owner       string
+	repo        string
+	teamMembers map[string][]string
+	orgMembers  map[string][]string
+}
+
+func newFixtures(config *policy.Config, opts Options) *fixtures {
+	f := &fixtures{
+		owner:       opts.Owner,
+		repo:        opts.Repo,
+		teamMembers: map[string][]string{},
+		orgMembers:  map[string][]string{},
+	}
+	for _, rule := range config.ApprovalRules {
+		for _, team := range rule.Requires.Actors.Teams {
+			if f.owner == "" {
+				f.owner, _, _ = strings.Cut(team, "/")
+			}
+			f.ensureTeam(team, max(rule.Requires.Count, 1))
+		}
+		for _, org := range rule.Requires.Actors.Organizations {
+			f.ensureOrg(org, max(rule.Requires.Count, 1))
+		}
+	}
+	if f.owner == "" {
+		f.owner = defaultOwner
+	}
+	if f.repo == "" {
+		f.repo = defaultRepo
+	}
+	return f
+}
+
+// defaultContext returns a new default context holding the fixtures
+func (f *fixtures) defaultContext() models.TestContext {
+	return models.NewTestContext(models.TestContext{
+		Owner:  f.owner,
+		Repo:   f.repo,
+		Author: defaultAuthor,
+		PR: models.TestPullRequest{
+			BaseRefName: defaultBaseBranch,
+			HeadRefName: defaultHeadBranch,
+		},
+		TeamMembers: f.teamMembers,
+		OrgMembers:  f.orgMembers,
+	})
+}
+
+// ensureTeam adds members to the team until it has at least n of them
+func (f *fixtures) ensureTeam(team string, n int) {
+	_, slug, found := strings.Cut(team, "/")
+	if !found {
+		slug = team
+	}
+	for i := len(f.teamMembers[team]); i < n; i++ {
+		f.teamMembers[team] = append(f.teamMembers[team], fmt.Sprintf("%s-member-%d", slug, i+1))
+	}
+}
+
+// ensureOrg adds members to the organization until it has at least n of them
+func (f *fixtures) ensureOrg(org string, n int) {
+	for i := len(f.orgMembers[org]); i < n; i++ {
+		f.orgMembers[org] = append(f.orgMembers[org], fmt.Sprintf("%s-member-%d", org, i+1))
+	}
+}
+
+// eligibleUsers returns the users that can approve as one of the actors
+func (f *fixtures) eligibleUsers(actors common.Actors, n int) []string {
+	users := slices.Clone(actors.Users)
+	for _, team := range actors.Teams {
+		users = append(users, f.teamMembers[team]...)
+	}
+	for _, org := range actors.Organizations {
+		users = append(users, f.orgMembers[org]...)
+	}
+	if len(users) < n && len(actors.GetPermissions()) > 0 {
+		team := f.owner + "/" + collaboratorsTeam
+		f.ensureTeam(team, n)
+		users = append(users, f.teamMembers[team]...)
+	}
+	slices.Sort(users)
+	return slices.Compact(users)
+}
+
+// approvals returns the reviews or comments approving the rule with the required count
+func (f *fixtures) approvals(rule *approval.Rule) ([]models.TestReview, []models.TestComment) {
+	count := rule.Requires.Count
+	if count <= 0 {
+		return nil, nil
+	}
+	users := f.eligibleUsers(rule.Requires.Actors, count)
+	users = users[:min(count, len(users))]
+
+	methods := rule.Options.GetMethods()
+	var reviews []models.TestReview
+	var comments []models.TestComment
+	if methods.IsGithubReview() {
+		for _, user := range users {
+			reviews = append(reviews, models.TestReview{Author: user, State: models.ReviewApproved})
+		}
+	} else if body, ok := example.ApprovalComment(methods); ok {
+		for _, user := range users {
+			comments = append(comments, models.TestComment{Author: user, Body: body})
+		}
+	}
+	return reviews, comments
+}
+
+// ruleContext returns a context satisfying the if conditions of the rule where possible
+func (f *fixtures) ruleContext(rule *approval.Rule) models.TestContext {
+	var tc models.TestContext
+	p := rule.Predicates
+	if p.ChangedFiles != nil {
+		if path, ok := example.Path(p.ChangedFiles.Paths, p.ChangedFiles.Globs); ok {
+			tc.FilesChanged = append(tc.FilesChanged, path)
+		}
+	}
+	if p.OnlyChangedFiles != nil {
+		if path, ok := example.Path(p.OnlyChangedFiles.Paths, p.OnlyChangedFiles.Globs); ok {
+			tc.FilesChanged = []string{path}
+		}
+	}
+	if p.FileAdded != nil {
+		if path, ok := example.Path(p.FileAdded.Paths, p.FileAdded.Globs); ok {
+			tc.FilesAdded = append(tc.FilesAdded, path)
+		}
+	}
+	if p.TargetsBranch != nil {
+		if branch, ok := example.ForRegexp(p.TargetsBranch.Pattern); ok {
+			tc.PR.BaseRefName = branch
+		}
+	}
+	if p.FromBranch != nil {
+		if branch, ok := example.ForRegexp(p.FromBranch.Pattern); ok {
+			tc.PR.HeadRefName = branch
+		}
+	}
+	if p.HasLabels != nil {
+		tc.Labels = slices.Clone(*p.HasLabels)
+	}
+	if p.HasAuthorIn != nil {
+		if users := f.eligibleUsers(p.HasAuthorIn.Actors, 1); len(users) > 0 {
+			tc.Author = users[0]
+		}
+	}
+	if p.HasStatus != nil {
+		conclusion := "success"
+		if len(p.HasStatus.Conclusions) > 0 {
+			conclusion = p.HasStatus.Conclusions[0]
+		}
+		tc.Statuses = map[string]models.Conclusion{}
+		for _, status := range p.HasStatus.Statuses {
+			tc.Statuses[status] = models.Conclusion(conclusion)
+		}
+	}
+	if p.CustomPropertyMatchesAnyOf != nil {
+		tc.CustomProperties = map[string]models.TestCustomProperty{}
+		for property, patterns := range *p.CustomPropertyMatchesAnyOf {
+			for _, pattern := range patterns {
+				if value, ok := example.ForRegexp(pattern); ok {
+					tc.CustomProperties[property] = models.TestCustomProperty{String: &value}
+					break
+				}
+			}
+		}
+	}
+	return tc
+}
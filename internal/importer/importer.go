@@ -0,0 +1,265 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// Files of a pull request dump, named after the GitHub REST API payloads they hold.
+// Only the pull request is required, missing files are treated as empty.
+// Commits have no equivalent in the test context, so they are not read.
+const (
+	// PullFile holds GET /repos/{owner}/{repo}/pulls/{number}
+	PullFile = "pull.json"
+	// FilesFile holds GET /repos/{owner}/{repo}/pulls/{number}/files
+	FilesFile = "files.json"
+	// ReviewsFile holds GET /repos/{owner}/{repo}/pulls/{number}/reviews
+	ReviewsFile = "reviews.json"
+	// CommentsFile holds GET /repos/{owner}/{repo}/issues/{number}/comments
+	CommentsFile = "comments.json"
+	// StatusesFile holds GET /repos/{owner}/{repo}/commits/{ref}/status, or the list of statuses
+	StatusesFile = "statuses.json"
+	// CheckRunsFile holds GET /repos/{owner}/{repo}/commits/{ref}/check-runs
+	CheckRunsFile = "check_runs.json"
+	// TeamsFile maps "org/team-slug" to GET /orgs/{org}/teams/{team_slug}/members
+	TeamsFile = "teams.json"
+	// OrgsFile maps "org" to GET /orgs/{org}/members
+	OrgsFile = "orgs.json"
+)
+
+type user struct {
+	Login string `json:"login"`
+}
+
+type pullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	User   user   `json:"user"`
+	Base   struct {
+		Ref  string `json:"ref"`
+		Repo struct {
+			Name  string `json:"name"`
+			Owner user   `json:"owner"`
+		} `json:"repo"`
+	} `json:"base"`
+	Head struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type file struct {
+	Filename string            `json:"filename"`
+	Status   models.FileStatus `json:"status"`
+}
+
+type review struct {
+	User  user   `json:"user"`
+	State string `json:"state"`
+}
+
+type comment struct {
+	User user   `json:"user"`
+	Body string `json:"body"`
+}
+
+type status struct {
+	Context string `json:"context"`
+	State   string `json:"state"`
+}
+
+type checkRuns struct {
+	CheckRuns []struct {
+		Name       string `json:"name"`
+		Conclusion string `json:"conclusion"`
+	} `json:"check_runs"`
+}
+
+// Import builds a test case from the pull request dump in a directory.
+// The test case is named after the pull request, it has no assertions.
+func Import(dir string) (models.TestCase, error) {
+	var pr pullRequest
+	if found, err := readJSON(dir, PullFile, &pr); err != nil {
+		return models.TestCase{}, err
+	} else if !found {
+		return models.TestCase{}, fmt.Errorf("missing %s in %s", PullFile, dir)
+	}
+
+	tc := models.TestContext{
+		Owner:  pr.Base.Repo.Owner.Login,
+		Repo:   pr.Base.Repo.Name,
+		Author: pr.User.Login,
+		PR: models.TestPullRequest{
+			BaseRefName: pr.Base.Ref,
+			HeadRefName: pr.Head.Ref,
+		},
+	}
+	for _, label := range pr.Labels {
+		tc.Labels = append(tc.Labels, label.Name)
+	}
+
+	var files []file
+	if _, err := readJSON(dir, FilesFile, &files); err != nil {
+		return models.TestCase{}, err
+	}
+	for _, f := range files {
+		switch f.Status {
+		case models.FileAdded:
+			tc.FilesAdded = append(tc.FilesAdded, f.Filename)
+		case models.FileRemoved:
+			tc.FilesDeleted = append(tc.FilesDeleted, f.Filename)
+		default:
+			tc.FilesChanged = append(tc.FilesChanged, f.Filename)
+		}
+	}
+
+	var reviews []review
+	if _, err := readJSON(dir, ReviewsFile, &reviews); err != nil {
+		return models.TestCase{}, err
+	}
+	for _, r := range reviews {
+		// The REST API reports review states in uppercase
+		state, err := models.ParseReviewState(strings.ToLower(r.State))
+		if err != nil {
+			return models.TestCase{}, fmt.Errorf("failed to parse %s: %w", ReviewsFile, err)
+		}
+		tc.Reviews = append(tc.Reviews, models.TestReview{Author: r.User.Login, State: state})
+	}
+
+	var comments []comment
+	if _, err := readJSON(dir, CommentsFile, &comments); err != nil {
+		return models.TestCase{}, err
+	}
+	for _, c := range comments {
+		tc.Comments = append(tc.Comments, models.TestComment{Author: c.User.Login, Body: c.Body})
+	}
+
+	statuses, err := readStatuses(dir)
+	if err != nil {
+		return models.TestCase{}, err
+	}
+	if len(statuses) > 0 {
+		tc.Statuses = statuses
+	}
+
+	if tc.TeamMembers, err = readMembers(dir, TeamsFile); err != nil {
+		return models.TestCase{}, err
+	}
+	if tc.OrgMembers, err = readMembers(dir, OrgsFile); err != nil {
+		return models.TestCase{}, err
+	}
+
+	return models.TestCase{
+		Name:    fmt.Sprintf("%s/%s#%d: %s", tc.Owner, tc.Repo, pr.Number, pr.Title),
+		Context: tc,
+	}, nil
+}
+
+// readStatuses reads the latest commit statuses and check run conclusions by context
+func readStatuses(dir string) (map[string]models.Conclusion, error) {
+	statuses := map[string]models.Conclusion{}
+
+	content, err := readFile(dir, StatusesFile)
+	if err != nil {
+		return nil, err
+	}
+	if content != nil {
+		// Both the combined status and the plain list of statuses are accepted
+		var list []status
+		var combined struct {
+			Statuses []status `json:"statuses"`
+		}
+		if err := json.Unmarshal(content, &list); err != nil {
+			if err := json.Unmarshal(content, &combined); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", StatusesFile, err)
+			}
+			list = combined.Statuses
+		}
+		// The list of statuses is ordered newest first, keep the latest state of each context
+		for _, s := range list {
+			if _, ok := statuses[s.Context]; ok {
+				continue
+			}
+			state, err := models.ParseConclusion(s.State)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", StatusesFile, err)
+			}
+			statuses[s.Context] = state
+		}
+	}
+
+	var runs checkRuns
+	if _, err := readJSON(dir, CheckRunsFile, &runs); err != nil {
+		return nil, err
+	}
+	for _, run := range runs.CheckRuns {
+		// Check runs still in progress have no conclusion yet
+		if run.Conclusion == "" {
+			continue
+		}
+		conclusion, err := models.ParseConclusion(run.Conclusion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", CheckRunsFile, err)
+		}
+		statuses[run.Name] = conclusion
+	}
+	return statuses, nil
+}
+
+// readMembers reads team or organization members, either as user objects or as plain logins
+func readMembers(dir, fileName string) (map[string][]string, error) {
+	var raw map[string][]json.RawMessage
+	if _, err := readJSON(dir, fileName, &raw); err != nil {
+		return nil, err
+	}
+	members := map[string][]string{}
+	for name, entries := range raw {
+		for _, entry := range entries {
+			var login string
+			if err := json.Unmarshal(entry, &login); err != nil {
+				var u user
+				if err := json.Unmarshal(entry, &u); err != nil {
+					return nil, fmt.Errorf("failed to parse member of %s in %s: %w", name, fileName, err)
+				}
+				login = u.Login
+			}
+			members[name] = append(members[name], login)
+		}
+		slices.Sort(members[name])
+	}
+	return members, nil
+}
+
+// readJSON unmarshals a file of the dump, it returns false if the file doesn't exist
+func readJSON(dir, fileName string, v any) (bool, error) {
+	content, err := readFile(dir, fileName)
+	if err != nil || content == nil {
+		return false, err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", fileName, err)
+	}
+	return true, nil
+}
+
+// readFile reads a file of the dump, it returns nil if the file doesn't exist
+func readFile(dir, fileName string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(dir, fileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load file %s: %w", fileName, err)
+	}
+	return content, nil
+}
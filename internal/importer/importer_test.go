@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+func writeDump(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const pull = `{"user": {"login": "dev"}, "base": {"ref": "main", "repo": {"name": "repo", "owner": {"login": "org"}}}, "head": {"ref": "feature"}}`
+
+func TestImportFileStatuses(t *testing.T) {
+	dir := writeDump(t, map[string]string{
+		PullFile: pull,
+		FilesFile: `[
+			{"filename": "new.go", "status": "added"},
+			{"filename": "old.go", "status": "removed"},
+			{"filename": "main.go", "status": "modified"},
+			{"filename": "moved.go", "status": "renamed"}
+		]`,
+	})
+	tc, err := Import(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tc.Context.FilesAdded, []string{"new.go"}) {
+		t.Errorf("files_added %v, expected [new.go]", tc.Context.FilesAdded)
+	}
+	if !slices.Equal(tc.Context.FilesDeleted, []string{"old.go"}) {
+		t.Errorf("files_deleted %v, expected [old.go]", tc.Context.FilesDeleted)
+	}
+	if !slices.Equal(tc.Context.FilesChanged, []string{"main.go", "moved.go"}) {
+		t.Errorf("files_changed %v, expected [main.go moved.go]", tc.Context.FilesChanged)
+	}
+}
+
+func TestImportStates(t *testing.T) {
+	dir := writeDump(t, map[string]string{
+		PullFile:      pull,
+		ReviewsFile:   `[{"user": {"login": "alice"}, "state": "APPROVED"}]`,
+		StatusesFile:  `{"statuses": [{"context": "ci", "state": "success"}, {"context": "ci", "state": "pending"}]}`,
+		CheckRunsFile: `{"check_runs": [{"name": "lint", "conclusion": "timed_out"}, {"name": "build", "conclusion": null}]}`,
+	})
+	tc, err := Import(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []models.TestReview{{Author: "alice", State: models.ReviewApproved}}; !slices.Equal(tc.Context.Reviews, want) {
+		t.Errorf("reviews %v, expected %v", tc.Context.Reviews, want)
+	}
+	if want := map[string]models.Conclusion{"ci": "success", "lint": "timed_out"}; !maps.Equal(tc.Context.Statuses, want) {
+		t.Errorf("statuses %v, expected %v", tc.Context.Statuses, want)
+	}
+}
+
+func TestImportInvalidValues(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		fileName string
+		content  string
+		want     string
+	}{
+		{
+			name:     "file status",
+			fileName: FilesFile,
+			content:  `[{"filename": "main.go", "status": "edited"}]`,
+			want:     `failed to parse files.json: invalid file status "edited", expected one of added, removed`,
+		},
+		{
+			name:     "review state",
+			fileName: ReviewsFile,
+			content:  `[{"user": {"login": "alice"}, "state": "APPROVE"}]`,
+			want:     `failed to parse reviews.json: invalid review state "approve", expected one of approved, changes_requested`,
+		},
+		{
+			name:     "commit status",
+			fileName: StatusesFile,
+			content:  `[{"context": "ci", "state": "ok"}]`,
+			want:     `failed to parse statuses.json: invalid conclusion "ok", expected one of success, failure`,
+		},
+		{
+			name:     "check run conclusion",
+			fileName: CheckRunsFile,
+			content:  `{"check_runs": [{"name": "lint", "conclusion": "passed"}]}`,
+			want:     `failed to parse check_runs.json: invalid conclusion "passed", expected one of success, failure`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir := writeDump(t, map[string]string{PullFile: pull, test.fileName: test.content})
+			_, err := Import(dir)
+			if err == nil {
+				t.Fatalf("expected an error for the invalid %s", test.name)
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("error %q doesn't contain %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/approval"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	yamlv2 "gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Checks performed by the linter
+const (
+	CheckInvalidPolicy      = "invalid-policy"
+	CheckInvalidRegexp      = "invalid-regexp"
+	CheckUndefinedRule      = "undefined-rule"
+	CheckUnreferencedRule   = "unreferenced-rule"
+	CheckUnknownTeam        = "unknown-team"
+	CheckUnsatisfiableCount = "unsatisfiable-count"
+	CheckUnreachableRule    = "unreachable-rule"
+)
+
+// regexpKeys are the policy keys holding regular expressions, directly or as a list
+var regexpKeys = []string{
+	"pattern", "paths", "ignore", "include", "exclude", "matches", "not_matches",
+	"comment_patterns", "github_review_comment_patterns", "body_patterns",
+}
+
+// propertyRegexpKeys are the policy keys mapping custom properties to lists of regular expressions
+var propertyRegexpKeys = []string{"custom_property_matches_any_of", "custom_property_matches_none_of"}
+
+// yamlErrorLine extracts the line number from YAML error messages
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// Issue is a problem found in a policy
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// Fixtures holds the team and organization members defined by the test files of a policy
+type Fixtures struct {
+	TeamMembers map[string][]string
+	OrgMembers  map[string][]string
+}
+
+// NewFixtures collects the members of the given test contexts. Keys are lowercased
+// like in the membership context used for evaluation.
+func NewFixtures(contexts ...models.TestContext) *Fixtures {
+	f := &Fixtures{TeamMembers: map[string][]string{}, OrgMembers: map[string][]string{}}
+	for _, tc := range contexts {
+		for team, members := range tc.TeamMembers {
+			f.TeamMembers[strings.ToLower(team)] = append(f.TeamMembers[strings.ToLower(team)], members...)
+		}
+		for org, members := range tc.OrgMembers {
+			f.OrgMembers[strings.ToLower(org)] = append(f.OrgMembers[strings.ToLower(org)], members...)
+		}
+	}
+	return f
+}
+
+// eligibleUsers returns the distinct users of the fixtures that are one of the actors.
+// Team members are write collaborators in the test context, so they count for permissions.
+func (f *Fixtures) eligibleUsers(actors common.Actors) []string {
+	users := slices.Clone(actors.Users)
+	for _, team := range actors.Teams {
+		users = append(users, f.TeamMembers[strings.ToLower(team)]...)
+	}
+	for _, org := range actors.Organizations {
+		users = append(users, f.OrgMembers[strings.ToLower(org)]...)
+	}
+	if len(actors.GetPermissions()) > 0 {
+		for _, members := range f.TeamMembers {
+			users = append(users, members...)
+		}
+	}
+	slices.Sort(users)
+	return slices.Compact(users)
+}
+
+// linter collects the issues of a single policy file
+type linter struct {
+	fileName string
+	issues   []Issue
+}
+
+func (l *linter) report(node *yaml.Node, check, format string, args ...any) {
+	issue := Issue{File: l.fileName, Line: 1, Column: 1, Check: check, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// Lint statically checks the content of a policy file. Checks against the test
+// fixtures are skipped if fixtures is nil.
+func Lint(fileName string, content []byte, fixtures *Fixtures) []Issue {
+	l := &linter{fileName: fileName}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		l.reportYAMLError(err)
+		return l.issues
+	}
+	l.checkRegexps(&root)
+	invalidRegexps := len(l.issues)
+
+	// References are checked on the YAML tree, so they are reported even if the policy doesn't load
+	rules := ruleNodes(&root)
+	l.checkReferences(&root, rules)
+
+	var config policy.Config
+	if err := yamlv2.UnmarshalStrict(content, &config); err != nil {
+		// Invalid regexes fail the unmarshalling, they are reported with positions already
+		if invalidRegexps == 0 {
+			l.reportYAMLError(err)
+		}
+	} else if fixtures != nil {
+		for i, rule := range config.ApprovalRules {
+			if i < len(rules) {
+				l.checkFixtures(rule, rules[i], fixtures)
+			}
+		}
+	}
+	slices.SortStableFunc(l.issues, func(a, b Issue) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return l.issues
+}
+
+// reportYAMLError reports a YAML error at the line its message mentions
+func (l *linter) reportYAMLError(err error) {
+	issue := Issue{File: l.fileName, Line: 1, Column: 1, Check: CheckInvalidPolicy, Message: err.Error()}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		issue.Line, _ = strconv.Atoi(m[1])
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// checkRegexps reports every regular expression of the policy that fails to compile
+func (l *linter) checkRegexps(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case slices.Contains(regexpKeys, key):
+				l.checkRegexpValues(key, value)
+			case slices.Contains(propertyRegexpKeys, key) && value.Kind == yaml.MappingNode:
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					l.checkRegexpValues(key+"."+value.Content[j].Value, value.Content[j+1])
+				}
+			}
+		}
+	}
+	for _, child := range node.Content {
+		l.checkRegexps(child)
+	}
+}
+
+func (l *linter) checkRegexpValues(key string, value *yaml.Node) {
+	values := []*yaml.Node{value}
+	if value.Kind == yaml.SequenceNode {
+		values = value.Content
+	}
+	for _, v := range values {
+		if v.Kind != yaml.ScalarNode {
+			continue
+		}
+		if _, err := regexp.Compile(v.Value); err != nil {
+			l.report(v, CheckInvalidRegexp, "invalid regular expression in %s: %v", key, err)
+		}
+	}
+}
+
+// checkReferences reports rules not referenced by the approval policy and references to undefined rules
+func (l *linter) checkReferences(root *yaml.Node, rules []*yaml.Node) {
+	defined := map[string]bool{}
+	var names []*yaml.Node
+	for _, rule := range rules {
+		if _, name := lookup(rule, "name"); name != nil && name.Kind == yaml.ScalarNode {
+			defined[name.Value] = true
+			names = append(names, name)
+		}
+	}
+
+	referenced := map[string]bool{}
+	_, approval := lookup(root, "policy", "approval")
+	for _, ref := range ruleReferences(approval) {
+		referenced[ref.Value] = true
+		if !defined[ref.Value] {
+			l.report(ref, CheckUndefinedRule, "policy references undefined rule %q", ref.Value)
+		}
+	}
+
+	for _, name := range names {
+		if !referenced[name.Value] {
+			l.report(name, CheckUnreferencedRule, "rule %q is not referenced by policy.approval", name.Value)
+		}
+	}
+}
+
+// ruleReferences returns the rule names referenced by an approval policy tree
+func ruleReferences(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []*yaml.Node{node}
+	case yaml.MappingNode:
+		// Only the values of the and/or conjunctions hold references
+		var refs []*yaml.Node
+		for i := 1; i < len(node.Content); i += 2 {
+			refs = append(refs, ruleReferences(node.Content[i])...)
+		}
+		return refs
+	}
+	var refs []*yaml.Node
+	for _, child := range node.Content {
+		refs = append(refs, ruleReferences(child)...)
+	}
+	return refs
+}
+
+// checkFixtures checks the requirements and actor predicates of a rule against the test fixtures
+func (l *linter) checkFixtures(rule *approval.Rule, node *yaml.Node, fixtures *Fixtures) {
+	_, teams := lookup(node, "requires", "teams")
+	for i, team := range rule.Requires.Actors.Teams {
+		if _, ok := fixtures.TeamMembers[strings.ToLower(team)]; ok {
+			continue
+		}
+		var teamNode *yaml.Node
+		if teams != nil && i < len(teams.Content) {
+			teamNode = teams.Content[i]
+		}
+		l.report(teamNode, CheckUnknownTeam, "rule %q requires team %s, which has no team_members in the test files", rule.Name, team)
+	}
+
+	if eligible := fixtures.eligibleUsers(rule.Requires.Actors); rule.Requires.Count > len(eligible) {
+		_, countNode := lookup(node, "requires", "count")
+		l.report(countNode, CheckUnsatisfiableCount, "rule %q requires %d approval(s), but the test files only have %d eligible user(s)",
+			rule.Name, rule.Requires.Count, len(eligible))
+	}
+
+	type actorPredicate struct {
+		name   string
+		actors *common.Actors
+	}
+	var actorPredicates []actorPredicate
+	if p := rule.Predicates.HasAuthorIn; p != nil {
+		actorPredicates = append(actorPredicates, actorPredicate{"has_author_in", &p.Actors})
+	}
+	if p := rule.Predicates.HasContributorIn; p != nil {
+		actorPredicates = append(actorPredicates, actorPredicate{"has_contributor_in", &p.Actors})
+	}
+	if p := rule.Predicates.OnlyHasContributorsIn; p != nil {
+		actorPredicates = append(actorPredicates, actorPredicate{"only_has_contributors_in", &p.Actors})
+	}
+	for _, predicate := range actorPredicates {
+		if len(fixtures.eligibleUsers(*predicate.actors)) > 0 {
+			continue
+		}
+		key, _ := lookup(node, "if", predicate.name)
+		l.report(key, CheckUnreachableRule, "rule %q can never apply, no user of the test files satisfies %s", rule.Name, predicate.name)
+	}
+}
+
+// ruleNodes returns the nodes of the approval rules, in definition order
+func ruleNodes(root *yaml.Node) []*yaml.Node {
+	_, rules := lookup(root, "approval_rules")
+	if rules == nil || rules.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return rules.Content
+}
+
+// lookup returns the key and value nodes at a path of mapping keys, or nils if the path doesn't exist
+func lookup(node *yaml.Node, path ...string) (key, value *yaml.Node) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	value = node
+	for _, k := range path {
+		if value.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+		var found bool
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			if value.Content[i].Value == k {
+				key, value, found = value.Content[i], value.Content[i+1], true
+				break
+			}
+		}
+		if !found {
+			return nil, nil
+		}
+	}
+	return key, value
+}
@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+func TestLint(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		policy   string
+		fixtures *Fixtures
+		want     []Issue
+	}{
+		{
+			name: "invalid regexp and undefined rule",
+			policy: `policy:
+  approval:
+  - review
+  - missing
+approval_rules:
+- name: review
+  if:
+    changed_files:
+      paths:
+      - "src/(.*"
+- name: unused
+`,
+			want: []Issue{
+				{Line: 4, Column: 5, Check: CheckUndefinedRule},
+				{Line: 10, Column: 9, Check: CheckInvalidRegexp},
+				{Line: 11, Column: 9, Check: CheckUnreferencedRule},
+			},
+		},
+		{
+			name: "unknown policy key",
+			policy: `policy:
+  approval:
+  - missing
+approval_rules:
+- name: review
+  unknown: true
+`,
+			want: []Issue{
+				{Line: 3, Column: 5, Check: CheckUndefinedRule},
+				{Line: 5, Column: 9, Check: CheckUnreferencedRule},
+				{Line: 6, Column: 1, Check: CheckInvalidPolicy},
+			},
+		},
+		{
+			name: "unknown team",
+			policy: `policy:
+  approval:
+  - review
+approval_rules:
+- name: review
+  requires:
+    count: 1
+    teams:
+    - org/reviewers
+    - org/admins
+`,
+			fixtures: NewFixtures(models.TestContext{TeamMembers: map[string][]string{"org/Reviewers": {"alice"}}}),
+			want: []Issue{
+				{Line: 10, Column: 7, Check: CheckUnknownTeam},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			issues := Lint("policy.yml", []byte(test.policy), test.fixtures)
+			got := make([]Issue, len(issues))
+			for i, issue := range issues {
+				got[i] = Issue{Line: issue.Line, Column: issue.Column, Check: issue.Check}
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got issues %+v, expected %+v", issues, test.want)
+			}
+		})
+	}
+}
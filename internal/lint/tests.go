@@ -0,0 +1,111 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/reegnz/policy-bot-tests/internal/coverage"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Checks performed on test files
+const (
+	CheckDuplicateTest = "duplicate-test"
+	CheckUnknownRule   = "unknown-rule"
+	CheckUnknownUser   = "unknown-user"
+)
+
+// CheckTests checks test cases for duplicate names, assertions on rules the policy
+// doesn't define and reviews by users who are not in any team. Test cases must be
+// bound to their policy file, the configurations are keyed by policy file.
+func CheckTests(tests *models.TestFile, configs map[string]*policy.Config) []Issue {
+	var issues []Issue
+	report := func(tc models.TestCase, node *yaml.Node, check, format string, args ...any) {
+		issue := Issue{File: tc.FileName, Line: tc.LineNumber, Column: 1, Check: check, Message: fmt.Sprintf(format, args...)}
+		if node != nil {
+			issue.Line, issue.Column = node.Line, node.Column
+		}
+		issues = append(issues, issue)
+	}
+
+	seen := map[string]models.TestCase{}
+	for _, tc := range tests.TestCases {
+		if first, ok := seen[tc.Name]; ok {
+			report(tc, tc.Node, CheckDuplicateTest, "duplicate test name %q, first defined at %s:%d", tc.Name, first.FileName, first.LineNumber)
+		} else {
+			seen[tc.Name] = tc
+		}
+
+		config := configs[tc.PolicyFile]
+		if config == nil {
+			continue
+		}
+		rules := map[string]bool{coverage.DisapprovalRule: true}
+		// Users allowed to approve by name, or by organization membership, are expected outside of teams
+		users := map[string]bool{}
+		for _, rule := range config.ApprovalRules {
+			rules[rule.Name] = true
+			for _, user := range rule.Requires.Actors.Users {
+				users[user] = true
+			}
+		}
+
+		for _, list := range []struct {
+			key   string
+			rules []string
+		}{
+			{"must_be_approved", tc.Assert.MustBeApproved},
+			{"must_be_pending", tc.Assert.MustBePending},
+			{"must_be_skipped", tc.Assert.MustBeSkipped},
+		} {
+			for i, rule := range list.rules {
+				if !rules[rule] {
+					report(tc, itemNode(tc.Node, i, "assert", list.key), CheckUnknownRule, "unknown rule %q in %s, the policy doesn't define it", rule, list.key)
+				}
+			}
+		}
+
+		// Reviews and members are checked as evaluated, with the default context of the test case merged in
+		merged := models.MergeContexts(tc.DefaultContextOr(tests.DefaultContext), tc.Context)
+		teamMembers := allMembers(merged.TeamMembers)
+		orgMembers := allMembers(merged.OrgMembers)
+		for i, review := range merged.Reviews {
+			if users[review.Author] || slices.Contains(teamMembers, review.Author) || slices.Contains(orgMembers, review.Author) {
+				continue
+			}
+			if len(tc.Context.Reviews) == 0 {
+				report(tc, tc.Node, CheckUnknownUser, "review by %s in the default context, who is not in any team of the test context", review.Author)
+				continue
+			}
+			node := itemNode(tc.Node, i, "context", "reviews")
+			if node != nil {
+				_, node = lookup(node, "author")
+			}
+			report(tc, node, CheckUnknownUser, "review by %s, who is not in any team of the test context", review.Author)
+		}
+	}
+	return issues
+}
+
+// allMembers returns the members of all teams or organizations of a membership map
+func allMembers(memberships map[string][]string) []string {
+	var members []string
+	for _, users := range memberships {
+		members = append(members, users...)
+	}
+	return members
+}
+
+// itemNode returns the node of the i-th item of the sequence at the path, or nil if there is none
+func itemNode(node *yaml.Node, i int, path ...string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	_, list := lookup(node, path...)
+	if list == nil || list.Kind != yaml.SequenceNode || i >= len(list.Content) {
+		return nil
+	}
+	return list.Content[i]
+}
@@ -0,0 +1,46 @@
+package lint
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+func TestCheckTestsReviews(t *testing.T) {
+	fileContext := models.TestContext{
+		TeamMembers: map[string][]string{"org/reviewers": {"alice"}},
+		Reviews:     []models.TestReview{{Author: "mallory", State: models.ReviewApproved}},
+	}
+	otherContext := models.TestContext{TeamMembers: map[string][]string{"org/admins": {"carol"}}}
+	tests := &models.TestFile{
+		DefaultContext: otherContext,
+		TestCases: []models.TestCase{
+			{Name: "default reviews", LineNumber: 1, DefaultContext: &fileContext},
+			{Name: "team member", LineNumber: 2, DefaultContext: &fileContext, Context: models.TestContext{
+				Reviews: []models.TestReview{{Author: "alice", State: models.ReviewApproved}},
+			}},
+			{Name: "member of test case team", LineNumber: 3, DefaultContext: &fileContext, Context: models.TestContext{
+				TeamMembers: map[string][]string{"org/security": {"bob"}},
+				Reviews:     []models.TestReview{{Author: "bob", State: models.ReviewApproved}},
+			}},
+			{Name: "member of other file", LineNumber: 4, DefaultContext: &fileContext, Context: models.TestContext{
+				Reviews: []models.TestReview{{Author: "carol", State: models.ReviewApproved}},
+			}},
+			{Name: "not loaded from a file", LineNumber: 5, Context: models.TestContext{
+				Reviews: []models.TestReview{{Author: "carol", State: models.ReviewApproved}},
+			}},
+		},
+	}
+
+	var got []int
+	for _, issue := range CheckTests(tests, map[string]*policy.Config{"": {}}) {
+		if issue.Check == CheckUnknownUser {
+			got = append(got, issue.Line)
+		}
+	}
+	if want := []int{1, 4}; !slices.Equal(got, want) {
+		t.Errorf("unknown users reported on lines %v, expected %v", got, want)
+	}
+}
@@ -0,0 +1,267 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateAssertions rewrites the assert blocks of the test cases starting at the given lines.
+// The YAML nodes are only used to locate the blocks, everything outside of them is kept as is,
+// so comments and formatting survive. Comments inside block-style assert blocks are carried over
+// to the keys and rules they belong to. Test cases without an assert block get one appended.
+func UpdateAssertions(fileName string, assertions map[int]models.TestAssertion) error {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to load file %s: %w", fileName, err)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return fmt.Errorf("failed to parse YAML in %s: %w", fileName, err)
+	}
+	testCases := mappingValue(&node, "test_cases")
+	if testCases == nil || testCases.Kind != yaml.SequenceNode {
+		return fmt.Errorf("no test_cases found in %s", fileName)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	seqIndent := max(sequenceIndent(&node), 0)
+	// Edit from the bottom up, so the line numbers of the remaining test cases stay valid
+	for i := len(testCases.Content) - 1; i >= 0; i-- {
+		testNode := testCases.Content[i]
+		assert, ok := assertions[testNode.Line]
+		if !ok || testNode.Kind != yaml.MappingNode || len(testNode.Content) == 0 {
+			continue
+		}
+		// Lines are 1-based in the YAML nodes, 0-based in the slice
+		start, end := lastLine(testNode), lastLine(testNode)
+		indent := testNode.Content[0].Column - 1
+		comment := ""
+		var comments assertComments
+		for j := 0; j < len(testNode.Content); j += 2 {
+			if key := testNode.Content[j]; key.Value == "assert" {
+				start, end = key.Line-1, blockEnd(lines, testNode.Content[j+1])
+				comment = key.LineComment
+				comments = collectAssertComments(testNode.Content[j+1])
+				break
+			}
+		}
+		block := renderAssertion(assert, indent, seqIndent, comment, comments)
+		lines = append(lines[:start], append(block, lines[end:]...)...)
+	}
+
+	if err := os.WriteFile(fileName, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", fileName, err)
+	}
+	return nil
+}
+
+// nodeComments holds the comments above, after and below a key or a list item
+type nodeComments struct {
+	head string
+	line string
+	foot string
+}
+
+// assertComments holds the comments inside an assert block,
+// of the keys of the block and of the list items by rule
+type assertComments struct {
+	keys  map[string]nodeComments
+	rules map[string]nodeComments
+}
+
+// collectAssertComments collects the comments inside a block-style assert block.
+// Comments inside flow-style blocks aren't located reliably by the YAML parser, so they're dropped.
+func collectAssertComments(node *yaml.Node) assertComments {
+	comments := assertComments{keys: map[string]nodeComments{}, rules: map[string]nodeComments{}}
+	if node.Kind != yaml.MappingNode || node.Style&yaml.FlowStyle != 0 {
+		return comments
+	}
+	// Comments below the last node of the block follow the replaced lines, so they're kept anyway
+	end := lastLine(node)
+	foot := func(nodes ...*yaml.Node) string {
+		var feet []string
+		for _, n := range nodes {
+			if n.FootComment != "" && lastLine(n) < end {
+				feet = append(feet, n.FootComment)
+			}
+		}
+		return strings.Join(feet, "\n")
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		// Comments after scalars are attached to the value, after the key of a list to the key
+		line := key.LineComment
+		if line == "" {
+			line = value.LineComment
+		}
+		comments.keys[key.Value] = nodeComments{head: key.HeadComment, line: line, foot: foot(key, value)}
+		if value.Kind == yaml.SequenceNode {
+			for _, item := range value.Content {
+				comments.rules[item.Value] = nodeComments{head: item.HeadComment, line: item.LineComment, foot: foot(item)}
+			}
+		}
+	}
+	return comments
+}
+
+// withComments renders the lines of a node with the head comment above them,
+// the line comment after the first one and the foot comment below them
+func withComments(prefix string, lines []string, comments nodeComments) []string {
+	var block []string
+	block = appendComment(block, prefix, comments.head)
+	lines[0] = prefix + lines[0]
+	if comments.line != "" {
+		lines[0] += " " + comments.line
+	}
+	block = append(block, lines...)
+	if comments.foot != "" {
+		// Foot comments are separated from the next node by an empty line
+		block = append(appendComment(block, prefix, comments.foot), "")
+	}
+	return block
+}
+
+// appendComment appends the lines of a comment at the given indentation
+func appendComment(lines []string, prefix, comment string) []string {
+	if comment == "" {
+		return lines
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		if line == "" {
+			lines = append(lines, "")
+		} else {
+			lines = append(lines, prefix+line)
+		}
+	}
+	return lines
+}
+
+// renderAssertion renders an assert block at the given indentation,
+// with the comments of the keys and rules that are still asserted
+func renderAssertion(assert models.TestAssertion, indent, seqIndent int, comment string, comments assertComments) []string {
+	prefix := strings.Repeat(" ", indent)
+	key := prefix + "assert:"
+	if comment != "" {
+		key += " " + comment
+	}
+	block := []string{key}
+	block = append(block, withComments(prefix+"  ", []string{fmt.Sprintf("evaluation_status: %s", assert.EvaluationStatus)}, comments.keys["evaluation_status"])...)
+	for _, list := range []struct {
+		key   string
+		rules []string
+	}{
+		{"must_be_approved", assert.MustBeApproved},
+		{"must_be_pending", assert.MustBePending},
+		{"must_be_skipped", assert.MustBeSkipped},
+	} {
+		if len(list.rules) == 0 {
+			continue
+		}
+		// The foot comment of the key follows the list items
+		lines := []string{list.key + ":"}
+		for _, rule := range list.rules {
+			lines = append(lines, withComments(prefix+"  "+strings.Repeat(" ", seqIndent), []string{"- " + yamlScalar(rule)}, comments.rules[rule])...)
+		}
+		block = append(block, withComments(prefix+"  ", lines, comments.keys[list.key])...)
+	}
+	return block
+}
+
+// yamlScalar quotes a string if it would not be read back as the same plain scalar
+func yamlScalar(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return s
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+// mappingValue returns the value of a key in the top-level mapping of a document
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lastLine returns the last line spanned by a node
+func lastLine(node *yaml.Node) int {
+	last := node.Line
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		last += strings.Count(strings.TrimSuffix(node.Value, "\n"), "\n") + 1
+	}
+	for _, child := range node.Content {
+		last = max(last, lastLine(child))
+	}
+	return last
+}
+
+// blockEnd returns the last line spanned by a node, including the closing bracket
+// of a flow collection, which the node doesn't record
+func blockEnd(lines []string, node *yaml.Node) int {
+	last := lastLine(node)
+	if node.Style&yaml.FlowStyle == 0 || (node.Kind != yaml.MappingNode && node.Kind != yaml.SequenceNode) {
+		return last
+	}
+	depth := 0
+	var quote rune
+	for i := node.Line - 1; i < len(lines); i++ {
+		line := []rune(lines[i])
+		if i == node.Line-1 {
+			// Columns count characters, not bytes
+			line = line[min(node.Column-1, len(line)):]
+		}
+	scan:
+		for j, c := range line {
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '#' && (j == 0 || line[j-1] == ' ' || line[j-1] == '\t'):
+				break scan
+			case c == '{' || c == '[':
+				depth++
+			case c == '}' || c == ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+	}
+	return last
+}
+
+// sequenceIndent returns how far block sequences are indented relative to their
+// parent key in the file, 0 for the indentless style used by the examples.
+// It returns -1 if the file has no block sequences.
+func sequenceIndent(node *yaml.Node) int {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind == yaml.SequenceNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0 && value.Content[0].Line > key.Line {
+				return max(value.Content[0].Column-key.Column-2, 0)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if indent := sequenceIndent(child); indent >= 0 {
+			return indent
+		}
+	}
+	return -1
+}
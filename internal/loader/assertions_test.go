@@ -0,0 +1,208 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+func TestUpdateAssertions(t *testing.T) {
+	approved := models.TestAssertion{
+		EvaluationStatus: models.StatusApproved,
+		MustBeApproved:   []string{"review"},
+	}
+
+	for _, test := range []struct {
+		name    string
+		content string
+		line    int
+		want    string
+	}{
+		{
+			name: "replaces block",
+			content: `test_cases:
+- name: a
+  assert:
+    evaluation_status: pending
+    must_be_pending:
+    - review
+- name: b
+`,
+			line: 2,
+			want: `test_cases:
+- name: a
+  assert:
+    evaluation_status: approved
+    must_be_approved:
+    - review
+- name: b
+`,
+		},
+		{
+			name: "appends missing block",
+			content: `test_cases:
+- name: a
+  context:
+    author: dev
+- name: b
+`,
+			line: 2,
+			want: `test_cases:
+- name: a
+  context:
+    author: dev
+  assert:
+    evaluation_status: approved
+    must_be_approved:
+    - review
+- name: b
+`,
+		},
+		{
+			name: "replaces flow-style block",
+			content: `test_cases:
+- name: a
+  assert: {evaluation_status: pending}
+- name: b
+`,
+			line: 2,
+			want: `test_cases:
+- name: a
+  assert:
+    evaluation_status: approved
+    must_be_approved:
+    - review
+- name: b
+`,
+		},
+		{
+			name: "replaces multi-line flow-style block",
+			content: `test_cases:
+- name: a
+  assert: { # see [docs]
+    evaluation_status: pending,
+    must_be_pending: [review]
+  }
+- name: b
+`,
+			line: 2,
+			want: `test_cases:
+- name: a
+  assert:
+    evaluation_status: approved
+    must_be_approved:
+    - review
+- name: b
+`,
+		},
+		{
+			name: "keeps comments around block",
+			content: `test_cases:
+- name: a
+  # the assertion
+  assert: # generated
+    # expected status
+    evaluation_status: pending
+    must_be_pending:
+    - review # the only rule
+  # after the block
+- name: b
+`,
+			line: 2,
+			want: `test_cases:
+- name: a
+  # the assertion
+  assert: # generated
+    # expected status
+    evaluation_status: approved
+    must_be_approved:
+    - review # the only rule
+  # after the block
+- name: b
+`,
+		},
+		{
+			name: "keeps comments of asserted keys and rules",
+			content: `test_cases:
+  - name: a
+    assert:
+      evaluation_status: pending   # wrong
+      # pending rules
+
+      must_be_pending: # not approved yet
+        # the rule under test
+        - review
+        # more to come
+
+        - docs # dropped with its rule
+      must_be_skipped: [lint] # dropped with its key
+      # below the block
+  - name: b
+`,
+			line: 2,
+			want: `test_cases:
+  - name: a
+    assert:
+      evaluation_status: approved # wrong
+      # pending rules
+
+      must_be_approved:
+        # the rule under test
+        - review
+        # more to come
+
+      # below the block
+  - name: b
+`,
+		},
+		{
+			name: "case on last line",
+			content: `test_cases:
+- name: a
+- name: b`,
+			line: 3,
+			want: `test_cases:
+- name: a
+- name: b
+  assert:
+    evaluation_status: approved
+    must_be_approved:
+    - review`,
+		},
+		{
+			name: "indented sequences",
+			content: `test_cases:
+  - name: a
+    assert:
+      evaluation_status: pending
+`,
+			line: 2,
+			want: `test_cases:
+  - name: a
+    assert:
+      evaluation_status: approved
+      must_be_approved:
+        - review
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "a.policy-tests.yml")
+			if err := os.WriteFile(fileName, []byte(test.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := UpdateAssertions(fileName, map[int]models.TestAssertion{test.line: approved}); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// readFile reads a file from the working tree, or from the git object
+// database at the given revision if ref is not empty.
+func readFile(fileName, ref string) ([]byte, error) {
+	if ref == "" {
+		return os.ReadFile(fileName)
+	}
+	dir, base := splitPath(fileName)
+	return git(dir, "show", ref+":./"+base)
+}
+
+// gitIsDir reports whether the path is a directory at the given revision
+func gitIsDir(path, ref string) (bool, error) {
+	dir, base := splitPath(path)
+	out, err := git(dir, "cat-file", "-t", ref+":./"+base)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) == "tree", nil
+}
+
+// gitListFiles lists all files below a directory at the given revision
+func gitListFiles(dir, ref string) ([]string, error) {
+	out, err := git(dir, "ls-tree", "-r", "--name-only", "-z", ref, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	return files, nil
+}
+
+// splitPath splits a path into its directory and base name, suitable for
+// addressing it relative to the directory in a git revision.
+func splitPath(path string) (dir, base string) {
+	path = filepath.Clean(path)
+	return filepath.Dir(path), filepath.Base(path)
+}
+
+// git runs a git command in the given directory and returns its output
+func git(dir string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -3,21 +3,156 @@ package loader
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/palantir/policy-bot/policy"
 	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
 	"gopkg.in/yaml.v2"
 )
 
-// LoadPolicyEvaluator loads and parses a policy configuration file
-func LoadPolicyEvaluator(fileName string) (common.Evaluator, error) {
-	policyFile, err := os.ReadFile(fileName)
+// defaultRemotePolicyPath is the policy path used when a remote reference omits it
+const defaultRemotePolicyPath = ".policy.yml"
+
+// PolicyOptions controls how policy files are read and resolved
+type PolicyOptions struct {
+	// RemoteRoots maps a remote repository ("org/repo") to a local directory
+	// holding a mirror of that repository. It is used to resolve policy files
+	// that only contain a remote reference.
+	RemoteRoots map[string]string
+
+	// Ref is the git revision to read policy files from. If empty, policy
+	// files are read from the working tree. Remote roots are always read from
+	// the working tree.
+	Ref string
+}
+
+// LoadPolicyConfig loads a policy configuration file.
+// If the file is a remote reference, the referenced policy is loaded from the
+// matching remote root instead.
+func LoadPolicyConfig(fileName string, opts PolicyOptions) (*policy.Config, error) {
+	fileName, policyFile, err := ReadPolicyFile(fileName, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load file %s: %w", fileName, err)
+		return nil, err
 	}
+
 	var policyConfig policy.Config
 	if err := yaml.UnmarshalStrict(policyFile, &policyConfig); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal file %s: %w", fileName, err)
 	}
-	return policy.ParsePolicy(&policyConfig, nil)
+	return &policyConfig, nil
+}
+
+// ReadPolicyFile reads the content of a policy file, following a remote reference
+// to the matching remote root. It returns the name of the file actually read.
+func ReadPolicyFile(fileName string, opts PolicyOptions) (string, []byte, error) {
+	policyFile, err := readFile(fileName, opts.Ref)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load file %s: %w", fileName, err)
+	}
+
+	remote, err := parseRemoteConfig(policyFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal file %s: %w", fileName, err)
+	}
+	if remote == nil {
+		return fileName, policyFile, nil
+	}
+	remoteFile, err := resolveRemotePolicy(remote, opts)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve remote policy referenced by %s: %w", fileName, err)
+	}
+	policyFile, err = os.ReadFile(remoteFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load file %s: %w", remoteFile, err)
+	}
+	if nested, _ := parseRemoteConfig(policyFile); nested != nil {
+		return "", nil, fmt.Errorf("remote policy %s must not reference another remote policy", remoteFile)
+	}
+	return remoteFile, policyFile, nil
+}
+
+// parseRemoteConfig returns the remote reference of a policy file, or nil if
+// the file is a regular policy.
+func parseRemoteConfig(content []byte) (*policy.RemoteConfig, error) {
+	var keys map[string]any
+	if err := yaml.Unmarshal(content, &keys); err != nil {
+		return nil, err
+	}
+	if _, ok := keys["remote"]; !ok {
+		return nil, nil
+	}
+	var remote policy.RemoteConfig
+	if err := yaml.UnmarshalStrict(content, &remote); err != nil {
+		return nil, err
+	}
+	return &remote, nil
+}
+
+// resolveRemotePolicy maps a remote reference to a file in a local mirror.
+// The ref of the remote reference is not resolved, the mirror is used as is.
+func resolveRemotePolicy(remote *policy.RemoteConfig, opts PolicyOptions) (string, error) {
+	root, ok := opts.RemoteRoots[remote.Remote]
+	if !ok {
+		return "", fmt.Errorf("no remote root configured for %s", remote.Remote)
+	}
+	path := remote.Path
+	if path == "" {
+		path = defaultRemotePolicyPath
+	}
+	return filepath.Join(root, path), nil
+}
+
+// LoadPolicyEvaluator loads and parses a policy configuration file
+func LoadPolicyEvaluator(fileName string, opts PolicyOptions) (common.Evaluator, error) {
+	policyConfig, err := LoadPolicyConfig(fileName, opts)
+	if err != nil {
+		return nil, err
+	}
+	return policy.ParsePolicy(policyConfig, nil)
+}
+
+// LoadPolicyConfigs loads the configuration of every policy referenced by the test cases.
+// Test cases that don't declare a policy are bound to defaultPolicy.
+// Each policy file is loaded only once, the returned map is keyed by policy file.
+func LoadPolicyConfigs(tests *models.TestFile, defaultPolicy string, opts PolicyOptions) (map[string]*policy.Config, error) {
+	configs := map[string]*policy.Config{}
+	for i := range tests.TestCases {
+		tc := &tests.TestCases[i]
+		if tc.PolicyFile == "" {
+			tc.PolicyFile = filepath.Clean(defaultPolicy)
+		}
+		if _, ok := configs[tc.PolicyFile]; ok {
+			continue
+		}
+		policyConfig, err := LoadPolicyConfig(tc.PolicyFile, opts)
+		if err != nil {
+			return nil, err
+		}
+		configs[tc.PolicyFile] = policyConfig
+	}
+	return configs, nil
+}
+
+// ParsePolicyEvaluators parses an evaluator for every policy configuration
+func ParsePolicyEvaluators(configs map[string]*policy.Config) (map[string]common.Evaluator, error) {
+	evaluators := map[string]common.Evaluator{}
+	for policyFile, policyConfig := range configs {
+		evaluator, err := policy.ParsePolicy(policyConfig, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy %s: %w", policyFile, err)
+		}
+		evaluators[policyFile] = evaluator
+	}
+	return evaluators, nil
+}
+
+// LoadPolicyEvaluators loads an evaluator for every policy referenced by the test cases.
+// See LoadPolicyConfigs for how test cases are bound to policies.
+func LoadPolicyEvaluators(tests *models.TestFile, defaultPolicy string, opts PolicyOptions) (map[string]common.Evaluator, error) {
+	configs, err := LoadPolicyConfigs(tests, defaultPolicy, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicyEvaluators(configs)
 }
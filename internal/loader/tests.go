@@ -9,9 +9,13 @@ import (
 	"strings"
 
 	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/schema"
 	"gopkg.in/yaml.v3"
 )
 
+// testFileSchema validates test files before decoding them, for errors with the path of the offending field
+var testFileSchema = schema.TestFile()
+
 // LoadTestFile loads and parses a single test configuration file.
 // It is a convenience wrapper around LoadTestFiles.
 func LoadTestFile(fileName string) (*models.TestFile, error) {
@@ -20,34 +24,22 @@ func LoadTestFile(fileName string) (*models.TestFile, error) {
 
 // LoadTestFiles loads and parses multiple test configuration files and merges them.
 func LoadTestFiles(paths []string) (*models.TestFile, error) {
-	var fileList []string
-	for _, path := range paths {
-		info, err := os.Stat(path)
-		if err != nil {
-			return nil, fmt.Errorf("failed to stat path %s: %w", path, err)
-		}
+	return LoadTestFilesAtRef(paths, "")
+}
 
-		if info.IsDir() {
-			err := filepath.WalkDir(path, func(s string, d fs.DirEntry, err error) error {
-				if err != nil {
-					return err
-				}
-				if strings.HasSuffix(d.Name(), ".policy-tests.yml") || strings.HasSuffix(d.Name(), ".policy-tests.yaml") {
-					fileList = append(fileList, s)
-				}
-				return nil
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to walk directory %s: %w", path, err)
-			}
-		} else {
-			fileList = append(fileList, path)
-		}
+// LoadTestFilesAtRef loads test configuration files like LoadTestFiles, but
+// reads them from the git object database at the given revision.
+// An empty ref reads them from the working tree.
+func LoadTestFilesAtRef(paths []string, ref string) (*models.TestFile, error) {
+	fileList, err := findTestFiles(paths, ref)
+	if err != nil {
+		return nil, err
 	}
 
-	mergedTests := &models.TestFile{}
+	// Test files without a default context still need one with non-nil maps
+	mergedTests := &models.TestFile{DefaultContext: models.NewTestContext(models.TestContext{})}
 	for _, file := range fileList {
-		content, err := os.ReadFile(file)
+		content, err := readFile(file, ref)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load file %s: %w", file, err)
 		}
@@ -57,6 +49,14 @@ func LoadTestFiles(paths []string) (*models.TestFile, error) {
 			return nil, fmt.Errorf("failed to parse YAML in %s: %w", file, err)
 		}
 
+		if errs := testFileSchema.Validate(&node); len(errs) > 0 {
+			lines := make([]string, len(errs))
+			for i, e := range errs {
+				lines[i] = fmt.Sprintf("  %s:%d:%d: %v", file, e.Line, e.Column, e)
+			}
+			return nil, fmt.Errorf("invalid test file %s:\n%s", file, strings.Join(lines, "\n"))
+		}
+
 		var tests models.TestFile
 		decoder := yaml.NewDecoder(bytes.NewReader(content))
 		decoder.KnownFields(true)
@@ -67,19 +67,30 @@ func LoadTestFiles(paths []string) (*models.TestFile, error) {
 
 		extractLineNumbers(&node, &tests)
 
-		// Set filename for all test cases from this file
+		// Policy paths are relative to the test file declaring them
+		policyFile := tests.Policy
+		if policyFile != "" && !filepath.IsAbs(policyFile) {
+			policyFile = filepath.Join(filepath.Dir(file), policyFile)
+		}
+
+		// Set filename, policy and default context for all test cases from this file
 		for i := range tests.TestCases {
+			tests.TestCases[i].PolicyFile = policyFile
+			tests.TestCases[i].DefaultContext = &tests.DefaultContext
+
 			// Get relative path from current working directory
 			relPath, err := filepath.Rel(".", file)
 			if err != nil {
-				// Fallback to base filename if relative path fails
-				tests.TestCases[i].FileName = filepath.Base(file)
+				// Fallback to the path as given if relative path fails,
+				// it still has to point at the file for snapshots
+				tests.TestCases[i].FileName = file
 			} else {
 				tests.TestCases[i].FileName = relPath
 			}
 		}
 
-		// Simple merge: append test cases, last defaultContext wins.
+		// Test cases keep the default context of their file, the merged one is the
+		// last declaring an owner, for test cases added by callers
 		mergedTests.TestCases = append(mergedTests.TestCases, tests.TestCases...)
 		if tests.DefaultContext.Owner != "" {
 			mergedTests.DefaultContext = tests.DefaultContext
@@ -89,7 +100,63 @@ func LoadTestFiles(paths []string) (*models.TestFile, error) {
 	return mergedTests, nil
 }
 
-// extractLineNumbers extracts line numbers from YAML nodes and sets them on test cases
+// findTestFiles expands directories into the test files they contain
+func findTestFiles(paths []string, ref string) ([]string, error) {
+	var fileList []string
+	for _, path := range paths {
+		if ref != "" {
+			isDir, err := gitIsDir(path, ref)
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat path %s at %s: %w", path, ref, err)
+			}
+			if !isDir {
+				fileList = append(fileList, path)
+				continue
+			}
+			files, err := gitListFiles(path, ref)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list directory %s at %s: %w", path, ref, err)
+			}
+			for _, f := range files {
+				if isTestFile(f) {
+					fileList = append(fileList, f)
+				}
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat path %s: %w", path, err)
+		}
+
+		if info.IsDir() {
+			err := filepath.WalkDir(path, func(s string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if isTestFile(d.Name()) {
+					fileList = append(fileList, s)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to walk directory %s: %w", path, err)
+			}
+		} else {
+			fileList = append(fileList, path)
+		}
+	}
+	return fileList, nil
+}
+
+// isTestFile reports whether the file name has a test file suffix
+func isTestFile(name string) bool {
+	return strings.HasSuffix(name, ".policy-tests.yml") || strings.HasSuffix(name, ".policy-tests.yaml")
+}
+
+// extractLineNumbers extracts line numbers from YAML nodes and sets them on test cases,
+// along with the nodes themselves
 func extractLineNumbers(node *yaml.Node, tests *models.TestFile) {
 	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
 		node = node.Content[0]
@@ -104,6 +171,7 @@ func extractLineNumbers(node *yaml.Node, tests *models.TestFile) {
 				for j, testNode := range value.Content {
 					if j < len(tests.TestCases) {
 						tests.TestCases[j].LineNumber = testNode.Line
+						tests.TestCases[j].Node = testNode
 					}
 				}
 			}
@@ -0,0 +1,37 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTestFilesInvalidEnum(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "a.policy-tests.yml")
+	content := `test_cases:
+- name: a
+  context:
+    reviews:
+    - author: alice
+      state: approve
+  assert:
+    evaluation_status: approved
+`
+	if err := os.WriteFile(fileName, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadTestFiles([]string{fileName})
+	if err == nil {
+		t.Fatal("expected an error for the invalid review state")
+	}
+	for _, want := range []string{
+		fileName + ":6:14:",
+		"expected one of approved, changes_requested, commented, dismissed, pending",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+}
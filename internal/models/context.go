@@ -262,6 +262,24 @@ func NewReviews(testReviews []TestReview) []*pull.Review {
 	return reviews
 }
 
+func NewStatuses(testStatuses map[string]Conclusion) map[string]string {
+	statuses := map[string]string{}
+	for name, conclusion := range testStatuses {
+		statuses[name] = string(conclusion)
+	}
+	return statuses
+}
+
+func NewWorkflowRuns(testWorkflowRuns map[string][]Conclusion) map[string][]string {
+	workflowRuns := map[string][]string{}
+	for workflow, conclusions := range testWorkflowRuns {
+		for _, conclusion := range conclusions {
+			workflowRuns[workflow] = append(workflowRuns[workflow], string(conclusion))
+		}
+	}
+	return workflowRuns
+}
+
 func NewComments(testComments []TestComment) []*pull.Comment {
 	comments := []*pull.Comment{}
 	for _, c := range testComments {
@@ -300,8 +318,8 @@ func NewGitHubContext(tc TestContext) *GitHubContext {
 		reviews:          NewReviews(tc.Reviews),
 		collaborators:    NewCollaborators(tc.TeamMembers),
 		labels:           tc.Labels,
-		statuses:         tc.Statuses,
-		workflowRuns:     tc.WorkflowRuns,
+		statuses:         NewStatuses(tc.Statuses),
+		workflowRuns:     NewWorkflowRuns(tc.WorkflowRuns),
 		comments:         NewComments(tc.Comments),
 		customProperties: NewCustomProperties(tc.CustomProperties),
 	}
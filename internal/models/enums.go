@@ -0,0 +1,139 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReviewState is the state of a review, as reported by GitHub in lowercase
+type ReviewState string
+
+const (
+	ReviewApproved         ReviewState = "approved"
+	ReviewChangesRequested ReviewState = "changes_requested"
+	ReviewCommented        ReviewState = "commented"
+	ReviewDismissed        ReviewState = "dismissed"
+	ReviewPending          ReviewState = "pending"
+)
+
+// Values returns the valid review states
+func (ReviewState) Values() []string {
+	return []string{
+		string(ReviewApproved), string(ReviewChangesRequested), string(ReviewCommented),
+		string(ReviewDismissed), string(ReviewPending),
+	}
+}
+
+func (s *ReviewState) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalEnum(value, "review state", s)
+}
+
+// ParseReviewState returns the review state of a value, rejecting values outside of the valid states
+func ParseReviewState(value string) (ReviewState, error) {
+	var s ReviewState
+	return s, parseEnum(value, "review state", &s)
+}
+
+// EvaluationStatus is the expected status of a policy or rule evaluation
+type EvaluationStatus string
+
+const (
+	StatusApproved    EvaluationStatus = "approved"
+	StatusPending     EvaluationStatus = "pending"
+	StatusDisapproved EvaluationStatus = "disapproved"
+	StatusSkipped     EvaluationStatus = "skipped"
+)
+
+// Values returns the valid evaluation statuses
+func (EvaluationStatus) Values() []string {
+	return []string{string(StatusApproved), string(StatusPending), string(StatusDisapproved), string(StatusSkipped)}
+}
+
+func (s *EvaluationStatus) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalEnum(value, "evaluation status", s)
+}
+
+// Conclusion is the state of a commit status or the conclusion of a check or workflow run
+type Conclusion string
+
+// Values returns the valid conclusions, commit status states come first
+func (Conclusion) Values() []string {
+	return []string{
+		"success", "failure", "error", "pending",
+		"neutral", "cancelled", "skipped", "timed_out", "action_required", "stale", "startup_failure",
+	}
+}
+
+func (c *Conclusion) UnmarshalYAML(value *yaml.Node) error {
+	return unmarshalEnum(value, "conclusion", c)
+}
+
+// ParseConclusion returns the conclusion of a value, rejecting values outside of the valid conclusions
+func ParseConclusion(value string) (Conclusion, error) {
+	var c Conclusion
+	return c, parseEnum(value, "conclusion", &c)
+}
+
+// FileStatus is the status of a file changed by a pull request, as reported by GitHub.
+// Test contexts list files by status in files_added, files_changed and files_deleted,
+// the importer sorts them into those lists.
+type FileStatus string
+
+const (
+	FileAdded     FileStatus = "added"
+	FileRemoved   FileStatus = "removed"
+	FileModified  FileStatus = "modified"
+	FileRenamed   FileStatus = "renamed"
+	FileCopied    FileStatus = "copied"
+	FileChanged   FileStatus = "changed"
+	FileUnchanged FileStatus = "unchanged"
+)
+
+// Values returns the valid file statuses
+func (FileStatus) Values() []string {
+	return []string{
+		string(FileAdded), string(FileRemoved), string(FileModified), string(FileRenamed),
+		string(FileCopied), string(FileChanged), string(FileUnchanged),
+	}
+}
+
+func (s *FileStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return parseEnum(value, "file status", s)
+}
+
+// enum is implemented by the string types with a fixed set of values
+type enum interface {
+	~string
+	Values() []string
+}
+
+// unmarshalEnum decodes a scalar into an enum, rejecting values outside of its set
+// with the line of the value and the allowed values
+func unmarshalEnum[T enum](value *yaml.Node, kind string, target *T) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	if err := parseEnum(s, kind, target); err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	return nil
+}
+
+// parseEnum sets an enum to a value, rejecting values outside of its set with the allowed values
+func parseEnum[T enum](s, kind string, target *T) error {
+	allowed := (*target).Values()
+	if !slices.Contains(allowed, s) {
+		return fmt.Errorf("invalid %s %q, expected one of %s", kind, s, strings.Join(allowed, ", "))
+	}
+	*target = T(s)
+	return nil
+}
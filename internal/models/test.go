@@ -1,20 +1,52 @@
 package models
 
-import "slices"
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
 
 // TestFile matches the root of the .policy-tests.yml file
 type TestFile struct {
-	DefaultContext TestContext `yaml:"default_context"`
-	TestCases      []TestCase  `yaml:"test_cases"`
+	Policy         string      `yaml:"policy,omitempty"`
+	DefaultContext TestContext `yaml:"default_context,omitempty"`
+	TestCases      []TestCase  `yaml:"test_cases,omitempty"`
 }
 
 // TestCase represents a single test case from the YAML file
 type TestCase struct {
-	Name       string        `yaml:"name"`
-	Context    TestContext   `yaml:"context"`
-	Assert     TestAssertion `yaml:"assert"`
-	LineNumber int           `yaml:"-"`
-	FileName   string        `yaml:"-"`
+	Name     string        `yaml:"name"`
+	Context  TestContext   `yaml:"context,omitempty"`
+	Assert   TestAssertion `yaml:"assert,omitempty"`
+	Snapshot bool          `yaml:"snapshot,omitempty"`
+	Tags     []string      `yaml:"tags,omitempty"`
+	// Skip is the reason the test case is skipped, it runs if empty
+	Skip string `yaml:"skip,omitempty"`
+	// ExpectFailure is the reason the assertions are known to fail, like the ticket of a policy bug
+	ExpectFailure string `yaml:"expect_failure,omitempty"`
+	// Only runs the test cases marked with it and skips all others
+	Only bool `yaml:"only,omitempty"`
+	// Timeout limits the evaluation time of the test case, overriding the --timeout flag
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	LineNumber int    `yaml:"-"`
+	FileName   string `yaml:"-"`
+	PolicyFile string `yaml:"-"`
+	// DefaultContext is the default context of the file the test case was loaded from
+	DefaultContext *TestContext `yaml:"-"`
+	// Node is the YAML node the test case was loaded from, used to report positions
+	Node *yaml.Node `yaml:"-"`
+}
+
+// DefaultContextOr returns the default context of the file the test case was
+// loaded from, or fallback if it wasn't loaded from a file
+func (tc TestCase) DefaultContextOr(fallback TestContext) TestContext {
+	if tc.DefaultContext != nil {
+		return *tc.DefaultContext
+	}
+	return fallback
 }
 
 type TestCustomProperty struct {
@@ -24,31 +56,31 @@ type TestCustomProperty struct {
 
 // TestContext is a simplified version of GitHubContext for easy YAML parsing
 type TestContext struct {
-	FilesChanged []string            `yaml:"files_changed"`
-	FilesAdded   []string            `yaml:"files_added"`
-	FilesDeleted []string            `yaml:"files_deleted"`
-	Author       string              `yaml:"author"`
-	Owner        string              `yaml:"owner"`
-	Repo         string              `yaml:"repo"`
-	PR           TestPullRequest     `yaml:"pr"`
-	Reviews      []TestReview        `yaml:"reviews"`
-	Statuses     map[string]string   `yaml:"statuses"`
-	WorkflowRuns map[string][]string `yaml:"workflow_runs"`
-	Labels       []string            `yaml:"labels"`
-	TeamMembers  map[string][]string `yaml:"team_members"`
-	OrgMembers   map[string][]string `yaml:"org_members"`
-	Comments     []TestComment       `yaml:"comments"`
-
-	CustomProperties map[string]TestCustomProperty `yaml:"custom_properties"`
+	FilesChanged []string                `yaml:"files_changed,omitempty"`
+	FilesAdded   []string                `yaml:"files_added,omitempty"`
+	FilesDeleted []string                `yaml:"files_deleted,omitempty"`
+	Author       string                  `yaml:"author,omitempty"`
+	Owner        string                  `yaml:"owner,omitempty"`
+	Repo         string                  `yaml:"repo,omitempty"`
+	PR           TestPullRequest         `yaml:"pr,omitempty"`
+	Reviews      []TestReview            `yaml:"reviews,omitempty"`
+	Statuses     map[string]Conclusion   `yaml:"statuses,omitempty"`
+	WorkflowRuns map[string][]Conclusion `yaml:"workflow_runs,omitempty"`
+	Labels       []string                `yaml:"labels,omitempty"`
+	TeamMembers  map[string][]string     `yaml:"team_members,omitempty"`
+	OrgMembers   map[string][]string     `yaml:"org_members,omitempty"`
+	Comments     []TestComment           `yaml:"comments,omitempty"`
+
+	CustomProperties map[string]TestCustomProperty `yaml:"custom_properties,omitempty"`
 }
 
 // NewTestContext returns a copy of the context with nil maps replaced by empty maps.
 func NewTestContext(tc TestContext) TestContext {
 	if tc.Statuses == nil {
-		tc.Statuses = map[string]string{}
+		tc.Statuses = map[string]Conclusion{}
 	}
 	if tc.WorkflowRuns == nil {
-		tc.WorkflowRuns = map[string][]string{}
+		tc.WorkflowRuns = map[string][]Conclusion{}
 	}
 	if tc.TeamMembers == nil {
 		tc.TeamMembers = map[string][]string{}
@@ -73,27 +105,27 @@ func NewTestFile(tf TestFile) TestFile {
 
 // TestPullRequest is a simplified version of a PR for YAML parsing
 type TestPullRequest struct {
-	BaseRefName string `yaml:"base_ref_name"`
-	HeadRefName string `yaml:"head_ref_name"`
+	BaseRefName string `yaml:"base_ref_name,omitempty"`
+	HeadRefName string `yaml:"head_ref_name,omitempty"`
 }
 
 // TestReview is a simplified version of a review for YAML parsing
 type TestReview struct {
-	Author string `yaml:"author"`
-	State  string `yaml:"state"`
+	Author string      `yaml:"author,omitempty"`
+	State  ReviewState `yaml:"state,omitempty"`
 }
 
 type TestComment struct {
-	Author string `yaml:"author"`
-	Body   string `yaml:"body"`
+	Author string `yaml:"author,omitempty"`
+	Body   string `yaml:"body,omitempty"`
 }
 
 // TestAssertion defines the expected outcomes of a test case
 type TestAssertion struct {
-	EvaluationStatus string   `yaml:"evaluation_status"`
-	MustBeApproved   []string `yaml:"must_be_approved"`
-	MustBePending    []string `yaml:"must_be_pending"`
-	MustBeSkipped    []string `yaml:"must_be_skipped"`
+	EvaluationStatus EvaluationStatus `yaml:"evaluation_status"`
+	MustBeApproved   []string         `yaml:"must_be_approved,omitempty"`
+	MustBePending    []string         `yaml:"must_be_pending,omitempty"`
+	MustBeSkipped    []string         `yaml:"must_be_skipped,omitempty"`
 }
 
 // AssertionResult holds the results of test assertions
@@ -112,7 +144,7 @@ type AssertionResult struct {
 func NewAssertionResult(assert TestAssertion, actualStatus string, approved, pending, skipped []string) AssertionResult {
 	return AssertionResult{
 		ActualStatus:     actualStatus,
-		ExpectedStatus:   assert.EvaluationStatus,
+		ExpectedStatus:   string(assert.EvaluationStatus),
 		ExpectedApproved: assert.MustBeApproved,
 		ActualApproved:   matchingItems(assert.MustBeApproved, approved),
 		ExpectedPending:  assert.MustBePending,
@@ -186,3 +218,65 @@ func (ar AssertionResult) HasMissingPending() bool {
 func (ar AssertionResult) HasMissingSkipped() bool {
 	return len(ar.MissingSkipped()) > 0
 }
+
+// MergeContexts merges test contexts with override precedence.
+// The maps of the base context are copied, not modified, nil maps are allocated
+// for contexts not built by NewTestContext.
+func MergeContexts(base, override TestContext) TestContext {
+	merged := NewTestContext(base)
+	merged.TeamMembers = maps.Clone(merged.TeamMembers)
+	merged.OrgMembers = maps.Clone(merged.OrgMembers)
+	merged.CustomProperties = maps.Clone(merged.CustomProperties)
+
+	if len(override.FilesChanged) > 0 {
+		merged.FilesChanged = override.FilesChanged
+	}
+	if len(override.FilesAdded) > 0 {
+		merged.FilesAdded = override.FilesAdded
+	}
+	if len(override.FilesDeleted) > 0 {
+		merged.FilesDeleted = override.FilesDeleted
+	}
+	if override.Owner != "" {
+		merged.Owner = override.Owner
+	}
+	if override.Repo != "" {
+		merged.Repo = override.Repo
+	}
+	if override.Author != "" {
+		merged.Author = override.Author
+	}
+	if override.PR.BaseRefName != "" {
+		merged.PR.BaseRefName = override.PR.BaseRefName
+	}
+	if override.PR.HeadRefName != "" {
+		merged.PR.HeadRefName = override.PR.HeadRefName
+	}
+
+	if len(override.Reviews) > 0 {
+		merged.Reviews = override.Reviews
+	}
+	if len(override.Statuses) > 0 {
+		merged.Statuses = override.Statuses
+	}
+	if len(override.WorkflowRuns) > 0 {
+		merged.WorkflowRuns = override.WorkflowRuns
+	}
+	if len(override.Labels) > 0 {
+		merged.Labels = override.Labels
+	}
+	if len(override.TeamMembers) > 0 {
+		maps.Copy(merged.TeamMembers, override.TeamMembers)
+	}
+	if len(override.OrgMembers) > 0 {
+		maps.Copy(merged.OrgMembers, override.OrgMembers)
+	}
+	if len(override.Comments) > 0 {
+		merged.Comments = override.Comments
+	}
+	if len(override.CustomProperties) > 0 {
+		maps.Copy(merged.CustomProperties, override.CustomProperties)
+	}
+
+	return merged
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeContexts(t *testing.T) {
+	override := TestContext{
+		Author:           "dev",
+		TeamMembers:      map[string][]string{"org/reviewers": {"alice"}},
+		OrgMembers:       map[string][]string{"org": {"bob"}},
+		CustomProperties: map[string]TestCustomProperty{"tier": {Array: []string{"gold"}}},
+	}
+
+	t.Run("base with nil maps", func(t *testing.T) {
+		merged := MergeContexts(TestContext{Owner: "org"}, override)
+		if merged.Owner != "org" || merged.Author != "dev" {
+			t.Errorf("merged owner %q and author %q, expected org and dev", merged.Owner, merged.Author)
+		}
+		if !slices.Equal(merged.TeamMembers["org/reviewers"], []string{"alice"}) || !slices.Equal(merged.OrgMembers["org"], []string{"bob"}) {
+			t.Errorf("merged members %v and %v", merged.TeamMembers, merged.OrgMembers)
+		}
+		if merged.Statuses == nil || merged.WorkflowRuns == nil || len(merged.CustomProperties) != 1 {
+			t.Errorf("merged context has nil maps or lost custom properties: %+v", merged)
+		}
+	})
+
+	t.Run("base maps are not modified", func(t *testing.T) {
+		base := NewTestContext(TestContext{TeamMembers: map[string][]string{"org/admins": {"carol"}}})
+		merged := MergeContexts(base, override)
+		if len(merged.TeamMembers) != 2 {
+			t.Errorf("merged teams %v, expected org/admins and org/reviewers", merged.TeamMembers)
+		}
+		if len(base.TeamMembers) != 1 || len(base.OrgMembers) != 0 || len(base.CustomProperties) != 0 {
+			t.Errorf("base context was modified: %+v", base)
+		}
+	})
+}
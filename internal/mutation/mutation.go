@@ -0,0 +1,167 @@
+package mutation
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/common"
+	"gopkg.in/yaml.v2"
+)
+
+// looseRegexp is the pattern path regexes are loosened to
+const looseRegexp = ".*"
+
+// Mutation is a single systematic change to a policy configuration
+type Mutation struct {
+	Description string
+	apply       func(config *policy.Config)
+}
+
+// Apply returns a mutated copy of the policy configuration
+func (m Mutation) Apply(config *policy.Config) (*policy.Config, error) {
+	mutant, err := clonePolicyConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	m.apply(mutant)
+	return mutant, nil
+}
+
+// clonePolicyConfig deep copies a policy configuration by round-tripping it through YAML
+func clonePolicyConfig(config *policy.Config) (*policy.Config, error) {
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy: %w", err)
+	}
+	var clone policy.Config
+	if err := yaml.UnmarshalStrict(content, &clone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policy: %w", err)
+	}
+	return &clone, nil
+}
+
+// Mutations returns all mutations applicable to the policy configuration
+func Mutations(config *policy.Config) []Mutation {
+	var mutations []Mutation
+	for i, rule := range config.ApprovalRules {
+		for j, team := range rule.Requires.Actors.Teams {
+			mutations = append(mutations, Mutation{
+				Description: fmt.Sprintf("rule %q: drop team %s from requires.teams", rule.Name, team),
+				apply: func(c *policy.Config) {
+					teams := &c.ApprovalRules[i].Requires.Actors.Teams
+					*teams = slices.Delete(*teams, j, j+1)
+				},
+			})
+		}
+		if count := rule.Requires.Count; count > 0 {
+			mutations = append(mutations, Mutation{
+				Description: fmt.Sprintf("rule %q: decrement requires.count from %d to %d", rule.Name, count, count-1),
+				apply: func(c *policy.Config) {
+					c.ApprovalRules[i].Requires.Count--
+				},
+			})
+		}
+		if p := rule.Predicates.ChangedFiles; p != nil {
+			for j, path := range p.Paths {
+				if path.String() == looseRegexp {
+					continue
+				}
+				mutations = append(mutations, Mutation{
+					Description: fmt.Sprintf("rule %q: loosen changed_files path %s to %s", rule.Name, path, looseRegexp),
+					apply: func(c *policy.Config) {
+						c.ApprovalRules[i].Predicates.ChangedFiles.Paths[j] = mustLooseRegexp()
+					},
+				})
+			}
+		}
+		if p := rule.Predicates.OnlyChangedFiles; p != nil {
+			for j, path := range p.Paths {
+				if path.String() == looseRegexp {
+					continue
+				}
+				mutations = append(mutations, Mutation{
+					Description: fmt.Sprintf("rule %q: loosen only_changed_files path %s to %s", rule.Name, path, looseRegexp),
+					apply: func(c *policy.Config) {
+						c.ApprovalRules[i].Predicates.OnlyChangedFiles.Paths[j] = mustLooseRegexp()
+					},
+				})
+			}
+		}
+	}
+
+	if len(config.Policy.Approval) > 1 {
+		mutations = append(mutations, Mutation{
+			Description: "policy.approval: swap the top-level and with or",
+			apply: func(c *policy.Config) {
+				c.Policy.Approval = []any{map[any]any{"or": []any(c.Policy.Approval)}}
+			},
+		})
+	}
+	mutations = append(mutations, approvalMutations(config.Policy.Approval, nil, "policy.approval")...)
+	return mutations
+}
+
+// approvalMutations returns the mutations of the approval policy tree below the given path.
+// The path holds the indices of the nested lists leading to the current list.
+func approvalMutations(list []any, path []int, location string) []Mutation {
+	var mutations []Mutation
+	for i, item := range list {
+		itemPath := append(slices.Clone(path), i)
+		switch item := item.(type) {
+		case string:
+			// Nested conjunctions must not be empty, only remove rules that have siblings
+			if len(path) > 0 && len(list) == 1 {
+				continue
+			}
+			mutations = append(mutations, Mutation{
+				Description: fmt.Sprintf("%s: remove rule %q", location, item),
+				apply: func(c *policy.Config) {
+					c.Policy.Approval = updateApprovalList(c.Policy.Approval, path, func(l []any) []any {
+						return slices.Delete(l, i, i+1)
+					})
+				},
+			})
+		case map[any]any:
+			for op, children := range item {
+				swapped, ok := map[string]string{"and": "or", "or": "and"}[fmt.Sprint(op)]
+				if !ok {
+					continue
+				}
+				mutations = append(mutations, Mutation{
+					Description: fmt.Sprintf("%s[%d]: swap %s with %s", location, i, op, swapped),
+					apply: func(c *policy.Config) {
+						c.Policy.Approval = updateApprovalList(c.Policy.Approval, path, func(l []any) []any {
+							l[i] = map[any]any{swapped: l[i].(map[any]any)[op]}
+							return l
+						})
+					},
+				})
+				if childList, ok := children.([]any); ok {
+					mutations = append(mutations, approvalMutations(childList, itemPath, fmt.Sprintf("%s[%d].%s", location, i, op))...)
+				}
+			}
+		}
+	}
+	return mutations
+}
+
+// updateApprovalList replaces the list of the approval policy tree at the given path
+func updateApprovalList(list []any, path []int, update func([]any) []any) []any {
+	if len(path) == 0 {
+		return update(list)
+	}
+	conjunction := list[path[0]].(map[any]any)
+	for op, children := range conjunction {
+		conjunction[op] = updateApprovalList(children.([]any), path[1:], update)
+	}
+	return list
+}
+
+func mustLooseRegexp() common.Regexp {
+	re, err := common.NewRegexp(looseRegexp)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
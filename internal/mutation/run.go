@@ -0,0 +1,120 @@
+package mutation
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+)
+
+// Errors returned by Run before testing any mutant
+var (
+	ErrNothingToRun = errors.New("no test case to run against the mutants, all are skipped or expected to fail")
+	ErrFailingSuite = errors.New("mutation testing requires a passing suite")
+)
+
+// Result is the outcome of running the test cases against a mutant
+type Result struct {
+	Mutation Mutation
+	// KilledBy is the name of the first test case failing against the mutant
+	KilledBy string
+	// Err is set if the mutant is not a valid policy
+	Err error
+}
+
+// Survived returns true if the mutant is valid and no test case failed against it
+func (r Result) Survived() bool {
+	return r.Err == nil && r.KilledBy == ""
+}
+
+// Run applies every mutation to the policy and runs the test cases against each mutant.
+// Test cases skipped by their skip or only markers, and test cases expected to fail,
+// are not run. It returns the number of surviving mutants.
+func Run(config *policy.Config, defaultContext models.TestContext, testCases []models.TestCase, verbosity int) (survived int, err error) {
+	testCases = runnable(testCases)
+	if len(testCases) == 0 {
+		return 0, ErrNothingToRun
+	}
+	evaluator, err := parse(config)
+	if err != nil {
+		return 0, err
+	}
+	if failing := firstFailing(evaluator, defaultContext, testCases); failing != "" {
+		return 0, fmt.Errorf("test case %q fails against the unmodified policy: %w", failing, ErrFailingSuite)
+	}
+
+	mutations := Mutations(config)
+	log.Printf("Running %d test case(s) against %d mutant(s)", len(testCases), len(mutations))
+	killed, invalid := 0, 0
+	for _, m := range mutations {
+		result := Test(m, config, defaultContext, testCases)
+		switch {
+		case result.Err != nil:
+			invalid++
+			if verbosity >= 1 {
+				log.Printf("⚪ INVALID: %s", m.Description)
+				log.Printf("    - %v", result.Err)
+			}
+		case result.Survived():
+			survived++
+			log.Printf("🧟 SURVIVED: %s", m.Description)
+		default:
+			killed++
+			if verbosity >= 1 {
+				log.Printf("💀 KILLED: %s", m.Description)
+				log.Printf("    - Killed by: %s", result.KilledBy)
+			}
+		}
+	}
+	log.Printf("\nSummary: %d / %d mutants killed, %d survived, %d invalid.", killed, killed+survived, survived, invalid)
+	return survived, nil
+}
+
+// Test runs the test cases against the mutant created by applying the mutation to the policy
+func Test(m Mutation, config *policy.Config, defaultContext models.TestContext, testCases []models.TestCase) Result {
+	mutant, err := m.Apply(config)
+	if err != nil {
+		return Result{Mutation: m, Err: err}
+	}
+	evaluator, err := parse(mutant)
+	if err != nil {
+		return Result{Mutation: m, Err: err}
+	}
+	return Result{Mutation: m, KilledBy: firstFailing(evaluator, defaultContext, testCases)}
+}
+
+func parse(config *policy.Config) (common.Evaluator, error) {
+	evaluator, err := policy.ParsePolicy(config, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	return evaluator, nil
+}
+
+// runnable returns the test cases that are run against the mutants. Expected failures
+// fail against the unmodified policy already, so they can't detect a mutant.
+func runnable(testCases []models.TestCase) []models.TestCase {
+	focused := runner.IsFocused(testCases)
+	var runnable []models.TestCase
+	for _, tc := range testCases {
+		if runner.SkipReason(tc, focused) == "" && tc.ExpectFailure == "" {
+			runnable = append(runnable, tc)
+		}
+	}
+	return runnable
+}
+
+// firstFailing returns the name of the first test case failing its assertions, if any
+func firstFailing(evaluator common.Evaluator, defaultContext models.TestContext, testCases []models.TestCase) string {
+	for _, tc := range testCases {
+		_, result := runner.EvaluateTestCase(evaluator, defaultContext, tc)
+		if !runner.CheckAssertions(tc.Assert, &result).Success() {
+			return tc.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package mutation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"gopkg.in/yaml.v2"
+)
+
+const testPolicy = `
+policy:
+  approval:
+  - review
+approval_rules:
+- name: review
+  requires:
+    count: 1
+    users:
+    - alice
+`
+
+func approvedBy(users ...string) models.TestContext {
+	tc := models.TestContext{}
+	for _, user := range users {
+		tc.Reviews = append(tc.Reviews, models.TestReview{Author: user, State: models.ReviewApproved})
+	}
+	return tc
+}
+
+func TestRunIgnoresCasesNotRun(t *testing.T) {
+	var config policy.Config
+	if err := yaml.UnmarshalStrict([]byte(testPolicy), &config); err != nil {
+		t.Fatal(err)
+	}
+	defaultContext := models.NewTestContext(models.TestContext{Owner: "org", Repo: "repo", Author: "dev"})
+	approved := models.TestAssertion{EvaluationStatus: models.StatusApproved, MustBeApproved: []string{"review"}}
+	pending := models.TestAssertion{EvaluationStatus: models.StatusPending, MustBePending: []string{"review"}}
+
+	passing := []models.TestCase{
+		{Name: "approved by alice", Context: approvedBy("alice"), Assert: approved},
+		{Name: "not approved", Context: approvedBy(), Assert: pending},
+	}
+	for _, test := range []struct {
+		name      string
+		testCases []models.TestCase
+	}{
+		{
+			name: "expected failure",
+			testCases: append(passing, models.TestCase{
+				Name: "approved by bob", Context: approvedBy("bob"), Assert: approved, ExpectFailure: "bob can't approve yet",
+			}),
+		},
+		{
+			name: "skipped",
+			testCases: append(passing, models.TestCase{
+				Name: "approved by bob", Context: approvedBy("bob"), Assert: approved, Skip: "bob can't approve yet",
+			}),
+		},
+		{
+			name: "not focused",
+			testCases: []models.TestCase{
+				{Name: "approved by alice", Context: approvedBy("alice"), Assert: approved, Only: true},
+				{Name: "approved by bob", Context: approvedBy("bob"), Assert: approved},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Run(&config, defaultContext, test.testCases, 0); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("nothing to run", func(t *testing.T) {
+		xfail := models.TestCase{Name: "approved by bob", Context: approvedBy("bob"), Assert: approved, ExpectFailure: "bob can't approve yet"}
+		if _, err := Run(&config, defaultContext, []models.TestCase{xfail}, 0); !errors.Is(err, ErrNothingToRun) {
+			t.Errorf("got error %v, expected %v", err, ErrNothingToRun)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/reegnz/policy-bot-tests/internal/coverage"
+)
+
+// PrintRuleCoverage prints a table of the rule statuses observed for every policy.
+// Statuses a rule can't reach are shown as "-", possible statuses that were
+// never observed as "never".
+func PrintRuleCoverage(reports []*coverage.RuleReport) {
+	for _, report := range reports {
+		covered, total := report.Covered()
+		log.Printf("\nRule coverage for %s: %.1f%% (%d / %d statuses)", report.PolicyFile, report.Percent(), covered, total)
+
+		var buf bytes.Buffer
+		w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		header := []string{"RULE"}
+		for _, status := range coverage.Statuses {
+			header = append(header, strings.ToUpper(status.String()))
+		}
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, rc := range report.Rules {
+			row := []string{rc.Name}
+			for _, status := range coverage.Statuses {
+				switch {
+				case !rc.IsPossible(status):
+					row = append(row, "-")
+				default:
+					row = append(row, countOrNever(rc.Seen[status]))
+				}
+			}
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		printTable(w, &buf)
+	}
+}
+
+// PrintPredicateCoverage prints a table of the predicate outcomes observed for every policy.
+// Outcomes that were never observed are shown as "never".
+func PrintPredicateCoverage(reports []*coverage.PredicateReport) {
+	for _, report := range reports {
+		covered, total := report.Covered()
+		log.Printf("\nPredicate coverage for %s: %.1f%% (%d / %d outcomes)", report.PolicyFile, report.Percent(), covered, total)
+
+		var buf bytes.Buffer
+		w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RULE\tPREDICATE\tSATISFIED\tUNSATISFIED")
+		for _, pc := range report.Predicates {
+			row := []string{pc.Rule, pc.Predicate, countOrNever(pc.Satisfied), countOrNever(pc.Unsatisfied)}
+			if pc.Errors > 0 {
+				row = append(row, fmt.Sprintf("(%d errors)", pc.Errors))
+			}
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		printTable(w, &buf)
+	}
+}
+
+func countOrNever(count int) string {
+	if count == 0 {
+		return "never"
+	}
+	return fmt.Sprint(count)
+}
+
+// printTable flushes the table writer and prints the buffered table with indentation
+func printTable(w *tabwriter.Writer, buf *bytes.Buffer) {
+	_ = w.Flush()
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		log.Printf("  %s", line)
+	}
+}
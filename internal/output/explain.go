@@ -0,0 +1,52 @@
+package output
+
+import (
+	"log"
+
+	"github.com/reegnz/policy-bot-tests/internal/coverage"
+	"github.com/reegnz/policy-bot-tests/internal/explain"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// PrintExplanation prints the narrative of a single test case evaluation
+func PrintExplanation(tc models.TestCase, mergedContext models.TestContext, e *explain.Explanation) {
+	log.Printf("🔎 %s (%s:%d)", tc.Name, tc.FileName, tc.LineNumber)
+	log.Printf("  - Evaluation status: %s %s: %s", statusIcon(e.Result.Status), e.Result.Status, e.Result.StatusDescription)
+	log.Println("  - Test Context:")
+	PrintTestContext(mergedContext, "    ")
+
+	log.Println("  - Rules:")
+	for _, rule := range e.Rules {
+		if !rule.Applied {
+			log.Printf("    - ⚪ %s: not referenced by the approval policy", rule.Name)
+			continue
+		}
+		log.Printf("    - %s %s: %s", statusIcon(rule.Status), rule.Name, rule.StatusDescription)
+		for _, skippedBy := range rule.SkippedBy {
+			log.Printf("      - Skipped by %s", skippedBy)
+		}
+		for _, condition := range rule.UnsatisfiedConditions {
+			log.Printf("      - Unsatisfied condition: %s", condition)
+		}
+		for _, a := range rule.Approvals {
+			if a.Counted {
+				log.Printf("      - Counted %s by %s (%s)", a.Kind, a.Author, a.Detail)
+			} else {
+				log.Printf("      - Ignored %s by %s (%s): %s", a.Kind, a.Author, a.Detail, a.Reason)
+			}
+		}
+		if rule.Suggestion != nil {
+			if rule.Suggestion.Verified {
+				log.Printf("      - To approve: %s", rule.Suggestion.Description)
+			} else {
+				log.Printf("      - To approve: %s (not verified by re-evaluating)", rule.Suggestion.Description)
+			}
+		}
+	}
+
+	for _, child := range e.Result.Children {
+		if child.Name == coverage.DisapprovalRule {
+			log.Printf("  - Disapproval: %s %s: %s", statusIcon(child.Status), child.Status, child.StatusDescription)
+		}
+	}
+}
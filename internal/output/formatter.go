@@ -38,7 +38,7 @@ func PrintTestContext(tc models.TestContext, indent string) {
 	}
 	if len(tc.WorkflowRuns) > 0 {
 		log.Printf("%s- Workflows:", indent)
-		for k, v := range tc.WorkflowRuns {
+		for k, v := range models.NewWorkflowRuns(tc.WorkflowRuns) {
 			log.Printf("%s  - %s: %s", indent, k, strings.Join(v, ", "))
 		}
 	}
@@ -46,19 +46,7 @@ func PrintTestContext(tc models.TestContext, indent string) {
 
 // PrintResultTree prints the policy evaluation result tree with proper formatting
 func PrintResultTree(result *common.Result, indent string, showSkipped bool) {
-	statusIcon := "⚪"
-	switch result.Status {
-	case common.StatusApproved:
-		statusIcon = "✅"
-	case common.StatusSkipped:
-		statusIcon = "💤"
-	case common.StatusPending:
-		statusIcon = "🟡"
-	case common.StatusDisapproved:
-		statusIcon = "❌"
-	}
-
-	log.Printf("%s- %s %s: %s\n", indent, statusIcon, result.Name, result.StatusDescription)
+	log.Printf("%s- %s %s: %s\n", indent, statusIcon(result.Status), result.Name, result.StatusDescription)
 
 	sortedChildren := sortResults(result.Children)
 
@@ -70,6 +58,21 @@ func PrintResultTree(result *common.Result, indent string, showSkipped bool) {
 	}
 }
 
+// statusIcon returns the icon of an evaluation status
+func statusIcon(status common.EvaluationStatus) string {
+	switch status {
+	case common.StatusApproved:
+		return "✅"
+	case common.StatusSkipped:
+		return "💤"
+	case common.StatusPending:
+		return "🟡"
+	case common.StatusDisapproved:
+		return "❌"
+	}
+	return "⚪"
+}
+
 // sortResults sorts a slice of results based on their status.
 // The sort order is Disapproved > Approved > Pending > Skipped.
 func sortResults(results []*common.Result) []*common.Result {
@@ -109,3 +112,27 @@ func printRuleSection(sectionName string, rules []string, indent string) {
 		log.Printf("%s  - %s\n", indent, rule)
 	}
 }
+
+// PrintCheckFailure prints the failure of an additional check.
+// The first line is printed as a list item, the following lines are indented below it.
+func PrintCheckFailure(failure string, indent string) {
+	title, details, _ := strings.Cut(failure, "\n")
+	log.Printf("%s- %s\n", indent, title)
+	if details == "" {
+		return
+	}
+	for _, line := range strings.Split(details, "\n") {
+		log.Printf("%s  %s\n", indent, line)
+	}
+}
+
+// PrintSuggestions prints the suggested fixes of a failing test case, if any
+func PrintSuggestions(suggestions []string, indent string) {
+	if len(suggestions) == 0 {
+		return
+	}
+	log.Printf("%s- Suggestions:\n", indent)
+	for _, suggestion := range suggestions {
+		log.Printf("%s  - %s\n", indent, suggestion)
+	}
+}
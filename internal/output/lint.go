@@ -0,0 +1,19 @@
+package output
+
+import (
+	"log"
+
+	"github.com/reegnz/policy-bot-tests/internal/lint"
+)
+
+// PrintLintIssues prints the issues found in policy files
+func PrintLintIssues(issues []lint.Issue) {
+	for _, issue := range issues {
+		log.Printf("❌ %s:%d: %s (%s)", issue.File, issue.Line, issue.Message, issue.Check)
+	}
+	if len(issues) == 0 {
+		log.Printf("✅ No issues found.")
+		return
+	}
+	log.Printf("\nFound %d issue(s).", len(issues))
+}
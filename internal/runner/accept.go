@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"slices"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/coverage"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// ActualAssertion returns the assertion describing the evaluation result exactly.
+// The disapproval policy is not a rule, so it isn't listed as skipped.
+func ActualAssertion(result *common.Result) models.TestAssertion {
+	approved, pending, skipped := collectRuleStatuses(withoutDisapproval(result))
+	return models.TestAssertion{
+		EvaluationStatus: models.EvaluationStatus(result.Status.String()),
+		MustBeApproved:   approved,
+		MustBePending:    pending,
+		MustBeSkipped:    skipped,
+	}
+}
+
+// withoutDisapproval returns the result tree without the disapproval result the root holds
+// along with the approval result
+func withoutDisapproval(result *common.Result) *common.Result {
+	root := *result
+	root.Children = slices.DeleteFunc(slices.Clone(result.Children), func(child *common.Result) bool {
+		return child.Name == coverage.DisapprovalRule && len(child.Children) == 0
+	})
+	return &root
+}
+
+// Acceptor collects the actual results of failing test cases, to rewrite their assertions.
+// It implements Observer.
+type Acceptor struct {
+	byFile map[string]map[int]models.TestAssertion
+	// Accepted lists the names of the test cases whose assertions are rewritten,
+	// once per test case even if it runs repeatedly
+	Accepted []string
+}
+
+// NewAcceptor creates an acceptor
+func NewAcceptor() *Acceptor {
+	return &Acceptor{byFile: map[string]map[int]models.TestAssertion{}}
+}
+
+// Observe records the actual assertion of the test case if its assertions fail.
+// Test cases expected to fail keep their assertions.
+func (a *Acceptor) Observe(tc models.TestCase, _ models.TestContext, result *common.Result) {
+	if tc.ExpectFailure != "" || CheckAssertions(tc.Assert, result).Success() {
+		return
+	}
+	if a.byFile[tc.FileName] == nil {
+		a.byFile[tc.FileName] = map[int]models.TestAssertion{}
+	}
+	if _, ok := a.byFile[tc.FileName][tc.LineNumber]; !ok {
+		a.Accepted = append(a.Accepted, tc.Name)
+	}
+	a.byFile[tc.FileName][tc.LineNumber] = ActualAssertion(result)
+}
+
+// Save rewrites the assert blocks of the failing test cases in their test files
+func (a *Acceptor) Save() error {
+	var fileNames []string
+	for fileName := range a.byFile {
+		fileNames = append(fileNames, fileName)
+	}
+	slices.Sort(fileNames)
+	for _, fileName := range fileNames {
+		if err := loader.UpdateAssertions(fileName, a.byFile[fileName]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
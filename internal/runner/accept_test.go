@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+func TestActualAssertionExcludesDisapproval(t *testing.T) {
+	result := &common.Result{
+		Name:   "policy",
+		Status: common.StatusApproved,
+		Children: []*common.Result{
+			{Name: "approval", Status: common.StatusApproved, Children: []*common.Result{
+				{Name: "review", Status: common.StatusApproved},
+				{Name: "docs", Status: common.StatusSkipped},
+			}},
+			{Name: "disapproval", Status: common.StatusSkipped},
+		},
+	}
+
+	assert := ActualAssertion(result)
+	if assert.EvaluationStatus != "approved" {
+		t.Errorf("evaluation status %s, expected approved", assert.EvaluationStatus)
+	}
+	if !slices.Equal(assert.MustBeApproved, []string{"review"}) {
+		t.Errorf("must_be_approved %v, expected [review]", assert.MustBeApproved)
+	}
+	if !slices.Equal(assert.MustBeSkipped, []string{"docs"}) {
+		t.Errorf("must_be_skipped %v, expected [docs]", assert.MustBeSkipped)
+	}
+	if len(result.Children) != 2 {
+		t.Errorf("result tree was modified")
+	}
+}
+
+// With --count, failing test cases are observed once per run but accepted once
+func TestAcceptorAcceptsRepeatedRunsOnce(t *testing.T) {
+	result := &common.Result{Name: "policy", Status: common.StatusApproved}
+	acceptor := NewAcceptor()
+	for range 3 {
+		for _, tc := range []models.TestCase{
+			{Name: "a", FileName: "a.policy-tests.yml", LineNumber: 2, Assert: models.TestAssertion{EvaluationStatus: models.StatusPending}},
+			{Name: "b", FileName: "a.policy-tests.yml", LineNumber: 5, Assert: models.TestAssertion{EvaluationStatus: models.StatusPending}},
+			{Name: "c", FileName: "a.policy-tests.yml", LineNumber: 8, Assert: models.TestAssertion{EvaluationStatus: models.StatusApproved}},
+		} {
+			acceptor.Observe(tc, models.TestContext{}, result)
+		}
+	}
+	if want := []string{"a", "b"}; !slices.Equal(acceptor.Accepted, want) {
+		t.Errorf("accepted %v, expected %v", acceptor.Accepted, want)
+	}
+}
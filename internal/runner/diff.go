@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"log"
+	"slices"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// statusAbsent is reported for rules that don't appear in an evaluation
+const statusAbsent = "absent"
+
+// TestCaseDiff describes how the evaluation of a test case changes between two policies
+type TestCaseDiff struct {
+	TestCase    models.TestCase
+	BaseStatus  string
+	HeadStatus  string
+	RuleChanges []RuleChange
+}
+
+// RuleChange describes the status change of a single rule
+type RuleChange struct {
+	Rule       string
+	BaseStatus string
+	HeadStatus string
+}
+
+// Changed returns true if the overall status or any rule status changed
+func (d TestCaseDiff) Changed() bool {
+	return d.BaseStatus != d.HeadStatus || len(d.RuleChanges) > 0
+}
+
+// RunDiff evaluates every test case against a base and a head policy and
+// reports the test cases whose evaluation changes. Assertions are ignored.
+func RunDiff(base, head common.Evaluator, tests *models.TestFile, filter string, outputFormat string) (changed bool, err error) {
+	filteredCases, err := FilterTestCases(tests.TestCases, filter)
+	if err != nil {
+		return false, err
+	}
+
+	if outputFormat == "pretty" {
+		log.Printf("Comparing %d of %d total test case(s)", len(filteredCases), len(tests.TestCases))
+	}
+	changedCount := 0
+	for _, tc := range filteredCases {
+		diff := DiffTestCase(base, head, tests.DefaultContext, tc)
+		if !diff.Changed() {
+			continue
+		}
+		changedCount++
+
+		switch outputFormat {
+		case "efm":
+			log.Printf("%s:%d:1: %s: %s -> %s", tc.FileName, tc.LineNumber, tc.Name, diff.BaseStatus, diff.HeadStatus)
+		case "pretty":
+			log.Printf("🔀 CHANGED: %s", tc.Name)
+			if diff.BaseStatus != diff.HeadStatus {
+				log.Printf("    - Evaluation status: %s -> %s", diff.BaseStatus, diff.HeadStatus)
+			}
+			for _, change := range diff.RuleChanges {
+				log.Printf("    - %s: %s -> %s", change.Rule, change.BaseStatus, change.HeadStatus)
+			}
+		}
+	}
+	if outputFormat == "pretty" {
+		log.Printf("\nSummary: %d / %d test outcomes changed.", changedCount, len(filteredCases))
+	}
+	return changedCount > 0, nil
+}
+
+// DiffTestCase evaluates a single test case against a base and a head policy
+func DiffTestCase(base, head common.Evaluator, defaultContext models.TestContext, tc models.TestCase) TestCaseDiff {
+	_, baseResult := EvaluateTestCase(base, defaultContext, tc)
+	_, headResult := EvaluateTestCase(head, defaultContext, tc)
+
+	baseRules := RuleStatuses(&baseResult)
+	headRules := RuleStatuses(&headResult)
+
+	var rules []string
+	for rule := range baseRules {
+		rules = append(rules, rule)
+	}
+	for rule := range headRules {
+		if _, ok := baseRules[rule]; !ok {
+			rules = append(rules, rule)
+		}
+	}
+	slices.Sort(rules)
+
+	diff := TestCaseDiff{
+		TestCase:   tc,
+		BaseStatus: baseResult.Status.String(),
+		HeadStatus: headResult.Status.String(),
+	}
+	for _, rule := range rules {
+		baseStatus, ok := baseRules[rule]
+		if !ok {
+			baseStatus = statusAbsent
+		}
+		headStatus, ok := headRules[rule]
+		if !ok {
+			headStatus = statusAbsent
+		}
+		if baseStatus != headStatus {
+			diff.RuleChanges = append(diff.RuleChanges, RuleChange{
+				Rule:       rule,
+				BaseStatus: baseStatus,
+				HeadStatus: headStatus,
+			})
+		}
+	}
+	return diff
+}
+
+// RuleStatuses maps the name of every rule of the evaluation tree to its status,
+// including the disapproval policy and rules that disapprove
+func RuleStatuses(result *common.Result) map[string]string {
+	statuses := map[string]string{}
+	collectLeafStatuses(result, statuses)
+	return statuses
+}
+
+// collectLeafStatuses recursively records the status of every leaf of an evaluation tree
+func collectLeafStatuses(result *common.Result, statuses map[string]string) {
+	if len(result.Children) > 0 {
+		for _, child := range result.Children {
+			collectLeafStatuses(child, statuses)
+		}
+		return
+	}
+	statuses[result.Name] = result.Status.String()
+}
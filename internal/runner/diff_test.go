@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// resultEvaluator returns a fixed evaluation result
+type resultEvaluator common.Result
+
+func (e resultEvaluator) Trigger() common.Trigger {
+	return common.TriggerAll
+}
+
+func (e resultEvaluator) Evaluate(context.Context, pull.Context) common.Result {
+	return common.Result(e)
+}
+
+// policyResult builds the root of an evaluation tree from the approval rule results and the disapproval status
+func policyResult(status, disapproval common.EvaluationStatus, rules ...*common.Result) resultEvaluator {
+	return resultEvaluator{
+		Name:   "policy",
+		Status: status,
+		Children: []*common.Result{
+			{Name: "approval", Status: status, Children: rules},
+			{Name: "disapproval", Status: disapproval},
+		},
+	}
+}
+
+func TestDiffTestCase(t *testing.T) {
+	base := policyResult(common.StatusApproved, common.StatusSkipped,
+		&common.Result{Name: "review", Status: common.StatusApproved},
+		&common.Result{Name: "docs", Status: common.StatusSkipped},
+	)
+	head := policyResult(common.StatusDisapproved, common.StatusDisapproved,
+		&common.Result{Name: "review", Status: common.StatusApproved},
+		&common.Result{Name: "security", Status: common.StatusPending},
+	)
+
+	diff := DiffTestCase(base, head, models.NewTestContext(models.TestContext{}), models.TestCase{Context: models.NewTestContext(models.TestContext{})})
+	if diff.BaseStatus != "approved" || diff.HeadStatus != "disapproved" {
+		t.Errorf("status %s -> %s, expected approved -> disapproved", diff.BaseStatus, diff.HeadStatus)
+	}
+	want := []RuleChange{
+		{Rule: "disapproval", BaseStatus: "skipped", HeadStatus: "disapproved"},
+		{Rule: "docs", BaseStatus: "skipped", HeadStatus: statusAbsent},
+		{Rule: "security", BaseStatus: statusAbsent, HeadStatus: "pending"},
+	}
+	if !slices.Equal(diff.RuleChanges, want) {
+		t.Errorf("rule changes %+v, expected %+v", diff.RuleChanges, want)
+	}
+}
@@ -2,88 +2,558 @@ package runner
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
-	"maps"
+	"math/rand"
 	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/palantir/policy-bot/policy/common"
 	"github.com/reegnz/policy-bot-tests/internal/models"
 	"github.com/reegnz/policy-bot-tests/internal/output"
 )
 
-// RunTests executes test cases against a policy evaluator
-func RunTests(evaluator common.Evaluator, tests *models.TestFile, verbosity int, filter string, outputFormat string) (passed bool) {
-	var filterRegex *regexp.Regexp
-	var err error
-	if filter != "" {
-		filterRegex, err = regexp.Compile(filter)
-		if err != nil {
-			log.Fatalf("Invalid filter regex: %v", err)
+// Observer is notified of the evaluation result of every executed test case
+type Observer interface {
+	Observe(tc models.TestCase, mergedContext models.TestContext, result *common.Result)
+}
+
+// Checker performs an additional check on the evaluation result of a test case.
+// It returns a description of the failure, or an empty string if the check passed.
+type Checker interface {
+	Check(tc models.TestCase, result *common.Result) (failure string)
+}
+
+// Advisor suggests how to fix a failing test case
+type Advisor interface {
+	Advise(tc models.TestCase, mergedContext models.TestContext, result *common.Result) (suggestions []string)
+}
+
+// Options controls how test cases are run and reported
+type Options struct {
+	Verbosity    int
+	Filter       string
+	OutputFormat string
+	// Tags selects the test cases with any of the tags, ExcludeTags drops the ones with any of the tags
+	Tags        []string
+	ExcludeTags []string
+
+	// Observers are notified of the evaluation result of every test case
+	Observers []Observer
+	// Checkers perform additional checks, a test case fails if any of them fails
+	Checkers []Checker
+	// Advisors suggest fixes for failing test cases in the pretty output
+	Advisors []Advisor
+	// Parallel is the number of test cases evaluated concurrently. Results are
+	// still checked and printed one test case at a time, in order.
+	Parallel int
+	// Timeout limits the evaluation time of every test case not declaring its own timeout, 0 means no limit
+	Timeout time.Duration
+
+	// FailFast stops the run after the first test case that doesn't pass
+	FailFast bool
+	// Shuffle runs the test cases in a random order derived from Seed
+	Shuffle bool
+	Seed    int64
+	// Count is the number of times every test case is run, at least 1
+	Count int
+}
+
+// Errors returned by RunTests before running any test case
+var (
+	ErrInvalidFilter  = errors.New("invalid filter regex")
+	ErrNoTestsMatched = errors.New("no test cases matched")
+)
+
+// RunTests executes test cases against the policy evaluators they are bound to.
+// Results are reported grouped by policy file. If the context is canceled, the
+// remaining test cases are skipped and the summary covers the ones that were run.
+// An error is returned if the options select no test case to run.
+func RunTests(ctx context.Context, evaluators map[string]common.Evaluator, tests *models.TestFile, opts Options) (passed bool, err error) {
+	filteredCases, err := SelectTestCases(tests.TestCases, opts)
+	if err != nil {
+		return false, err
+	}
+	focused := IsFocused(filteredCases)
+
+	outputFormat := opts.OutputFormat
+	count := max(opts.Count, 1)
+	if outputFormat == "pretty" {
+		if count > 1 {
+			log.Printf("Running %d of %d total test case(s), %d times each", len(filteredCases), len(tests.TestCases), count)
+		} else {
+			log.Printf("Running %d of %d total test case(s)", len(filteredCases), len(tests.TestCases))
 		}
 	}
 
-	var filteredCases []models.TestCase
-	if filterRegex != nil {
-		for _, tc := range tests.TestCases {
-			if filterRegex.MatchString(tc.Name) {
-				filteredCases = append(filteredCases, tc)
+	// Runs point at the selected test cases, repeating them doesn't copy them
+	runs := make([]*models.TestCase, 0, len(filteredCases)*count)
+	for i := range filteredCases {
+		for range count {
+			runs = append(runs, &filteredCases[i])
+		}
+	}
+	if opts.Shuffle {
+		rand.New(rand.NewSource(opts.Seed)).Shuffle(len(runs), func(i, j int) {
+			runs[i], runs[j] = runs[j], runs[i]
+		})
+	}
+	groups := groupByPolicy(runs)
+	ordered := make([]*models.TestCase, 0, len(runs))
+	for _, group := range groups {
+		ordered = append(ordered, group.TestCases...)
+	}
+
+	// Canceling stops the workers when failing fast, ctx is only canceled by interrupts
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	evaluations := evaluateConcurrently(runCtx, evaluators, tests.DefaultContext, ordered, focused, opts)
+
+	multiplePolicies := len(groups) > 1
+	var total tally
+	groupTallies := make([]tally, len(groups))
+	stopped := false
+run:
+	for i, group := range groups {
+		if outputFormat == "pretty" && multiplePolicies {
+			log.Printf("\nPolicy: %s", group.PolicyFile)
+		}
+		for _, run := range group.TestCases {
+			tc := *run
+			e, ok := nextEvaluation(ctx, evaluations)
+			if !ok || ctx.Err() != nil {
+				break run
+			}
+
+			prefix := ""
+			if multiplePolicies {
+				prefix = "[" + group.PolicyFile + "] "
+			}
+			if e.skip != "" {
+				reportSkip(e, tc, outputFormat, prefix)
+				groupTallies[i].skipped++
+				continue
+			}
+			groupTallies[i].run++
+			if e.err != nil {
+				reportError(e, tc, outputFormat, prefix)
+				groupTallies[i].timedOut++
+			} else {
+				switch runTestCase(e, tc, opts, prefix) {
+				case outcomePass:
+					groupTallies[i].passed++
+					continue
+				case outcomeExpectedFailure:
+					groupTallies[i].expectedFailures++
+					continue
+				case outcomeUnexpectedPass:
+					groupTallies[i].unexpectedPasses++
+				}
+			}
+			if opts.FailFast {
+				stopped = true
+				cancel()
+				break run
 			}
 		}
-	} else {
-		filteredCases = tests.TestCases
+	}
+	for _, t := range groupTallies {
+		total.add(t)
 	}
 
+	if outputFormat == "pretty" {
+		if ctx.Err() != nil {
+			log.Printf("\nInterrupted after %d of %d test case(s).", total.run, len(runs))
+		} else if stopped {
+			log.Printf("\nStopped at the first failure after %d of %d test case(s).", total.run, len(runs))
+		}
+		log.Printf("\nSummary: %s", total)
+		if multiplePolicies {
+			for i, group := range groups {
+				log.Printf("  - %s: %s", group.PolicyFile, groupTallies[i])
+			}
+		}
+		if opts.Shuffle {
+			log.Printf("Shuffled with seed %d, rerun with --shuffle=%d to reproduce the order.", opts.Seed, opts.Seed)
+		}
+	}
+	passed = ctx.Err() == nil && !stopped && total.passed+total.expectedFailures == total.run
+	return passed, nil
+}
+
+// tally counts the outcomes of the test cases that were run
+type tally struct {
+	run              int
+	passed           int
+	expectedFailures int
+	unexpectedPasses int
+	timedOut         int
+	skipped          int
+}
+
+func (t *tally) add(other tally) {
+	t.run += other.run
+	t.passed += other.passed
+	t.expectedFailures += other.expectedFailures
+	t.unexpectedPasses += other.unexpectedPasses
+	t.timedOut += other.timedOut
+	t.skipped += other.skipped
+}
+
+func (t tally) String() string {
+	s := fmt.Sprintf("%d / %d tests passed", t.passed, t.run)
+	if t.expectedFailures > 0 {
+		s += fmt.Sprintf(", %d failed as expected", t.expectedFailures)
+	}
+	if t.unexpectedPasses > 0 {
+		s += fmt.Sprintf(", %d passed unexpectedly", t.unexpectedPasses)
+	}
+	if t.timedOut > 0 {
+		s += fmt.Sprintf(", %d timed out", t.timedOut)
+	}
+	if t.skipped > 0 {
+		s += fmt.Sprintf(", %d skipped", t.skipped)
+	}
+	return s + "."
+}
+
+// describeSelection describes the options selecting test cases, for when none matched
+func describeSelection(opts Options) string {
+	var parts []string
+	if opts.Filter != "" {
+		parts = append(parts, "filter: "+opts.Filter)
+	}
+	if len(opts.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(opts.Tags, ","))
+	}
+	if len(opts.ExcludeTags) > 0 {
+		parts = append(parts, "excluded tags: "+strings.Join(opts.ExcludeTags, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SelectTestCases returns the test cases matching the filter and tags of the options.
+// It returns ErrNoTestsMatched if there are none.
+func SelectTestCases(testCases []models.TestCase, opts Options) ([]models.TestCase, error) {
+	filteredCases, err := FilterTestCases(testCases, opts.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	filteredCases = FilterByTags(filteredCases, opts.Tags, opts.ExcludeTags)
+
 	if len(filteredCases) == 0 {
-		log.Printf("No test cases matched the filter: %s", filter)
-		return true
+		if selection := describeSelection(opts); selection != "" {
+			return nil, fmt.Errorf("%w the %s", ErrNoTestsMatched, selection)
+		}
+		return nil, fmt.Errorf("%w, the test files have none", ErrNoTestsMatched)
 	}
+	return filteredCases, nil
+}
 
-	if outputFormat == "pretty" {
-		log.Printf("Running %d of %d total test case(s)", len(filteredCases), len(tests.TestCases))
+// IsFocused reports whether any of the test cases is marked only: true,
+// in which case they are run alone and the others are skipped
+func IsFocused(testCases []models.TestCase) bool {
+	return slices.ContainsFunc(testCases, func(tc models.TestCase) bool { return tc.Only })
+}
+
+// FilterTestCases returns the test cases with names matching the filter regex
+func FilterTestCases(testCases []models.TestCase, filter string) ([]models.TestCase, error) {
+	if filter == "" {
+		return testCases, nil
+	}
+	filterRegex, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, err
+	}
+	var filteredCases []models.TestCase
+	for _, tc := range testCases {
+		if filterRegex.MatchString(tc.Name) {
+			filteredCases = append(filteredCases, tc)
+		}
 	}
-	passedCount := 0
-	for _, tc := range filteredCases {
-		mergedContext := MergeContexts(tests.DefaultContext, tc.Context)
-		pullContext := models.NewGitHubContext(mergedContext)
-		result := evaluator.Evaluate(context.Background(), pullContext)
+	return filteredCases, nil
+}
 
-		assertionResult := CheckAssertions(tc.Assert, &result)
-		pass := assertionResult.Success()
+// FilterByTags returns the test cases with any of the tags, or all test cases if no tags
+// are given, without the ones having any of the excluded tags
+func FilterByTags(testCases []models.TestCase, tags, excludeTags []string) []models.TestCase {
+	if len(tags) == 0 && len(excludeTags) == 0 {
+		return testCases
+	}
+	hasAny := func(tc models.TestCase, tags []string) bool {
+		return slices.ContainsFunc(tc.Tags, func(tag string) bool { return slices.Contains(tags, tag) })
+	}
+	var filteredCases []models.TestCase
+	for _, tc := range testCases {
+		if (len(tags) == 0 || hasAny(tc, tags)) && !hasAny(tc, excludeTags) {
+			filteredCases = append(filteredCases, tc)
+		}
+	}
+	return filteredCases
+}
 
-		switch outputFormat {
-		case "efm":
-			if !pass {
-				log.Printf("%s:%d:1: %s", tc.FileName, tc.LineNumber, tc.Name)
+// SkipReason returns why a test case is skipped, or an empty string if it runs
+func SkipReason(tc models.TestCase, focused bool) string {
+	switch {
+	case tc.Skip != "":
+		return tc.Skip
+	case focused && !tc.Only:
+		return "other test cases are marked only"
+	}
+	return ""
+}
+
+// policyGroup holds the test cases bound to a single policy file
+type policyGroup struct {
+	PolicyFile string
+	TestCases  []*models.TestCase
+}
+
+// groupByPolicy groups test cases by policy file, in order of first appearance
+func groupByPolicy(testCases []*models.TestCase) []policyGroup {
+	var groups []policyGroup
+	index := map[string]int{}
+	for _, tc := range testCases {
+		i, ok := index[tc.PolicyFile]
+		if !ok {
+			i = len(groups)
+			index[tc.PolicyFile] = i
+			groups = append(groups, policyGroup{PolicyFile: tc.PolicyFile})
+		}
+		groups[i].TestCases = append(groups[i].TestCases, tc)
+	}
+	return groups
+}
+
+// evaluation is the evaluation result of a test case, along with the context it was evaluated in
+type evaluation struct {
+	mergedContext models.TestContext
+	result        common.Result
+	// err is set if the evaluation didn't finish in time
+	err     error
+	timeout time.Duration
+	// skip is the reason the test case wasn't evaluated
+	skip string
+}
+
+// evaluateConcurrently evaluates the test cases on a pool of workers, each with
+// its own pull request context. The evaluations are delivered in test case order,
+// each on its own channel, so they can be consumed in order while later ones are running.
+// At most twice as many test cases as workers are evaluated ahead of the consumer,
+// receiving the channel of an evaluation lets the next test case start.
+// No more test cases are started once the context is canceled.
+func evaluateConcurrently(ctx context.Context, evaluators map[string]common.Evaluator, defaultContext models.TestContext, testCases []*models.TestCase, focused bool, opts Options) <-chan chan evaluation {
+	type job struct {
+		tc     *models.TestCase
+		result chan<- evaluation
+	}
+	workers := max(opts.Parallel, 1)
+	evaluations := make(chan chan evaluation, 2*workers)
+	jobs := make(chan job)
+	go func() {
+		defer close(jobs)
+		defer close(evaluations)
+		for _, tc := range testCases {
+			result := make(chan evaluation, 1)
+			select {
+			case evaluations <- result:
+			case <-ctx.Done():
+				return
 			}
-		case "pretty":
-			if pass {
-				log.Printf("✅ PASS: %s", tc.Name)
-			} else {
-				log.Printf("❌ FAIL: %s", tc.Name)
+			select {
+			case jobs <- job{tc, result}:
+			case <-ctx.Done():
+				return
 			}
-			indent := "    "
-			if !pass || verbosity >= 1 {
-				if verbosity >= 3 {
-					log.Println("  - Test Context:")
-					output.PrintTestContext(mergedContext, indent)
+		}
+	}()
+	for range workers {
+		go func() {
+			for j := range jobs {
+				if reason := SkipReason(*j.tc, focused); reason != "" {
+					j.result <- evaluation{skip: reason}
+					continue
 				}
-				if !pass || verbosity >= 1 {
-					output.PrintAssertionResult(assertionResult, verbosity, indent)
+				timeout := opts.Timeout
+				if j.tc.Timeout > 0 {
+					timeout = j.tc.Timeout
 				}
-				log.Println("  - Policy Evaluation Tree:")
-				output.PrintResultTree(&result, indent, verbosity >= 3)
+				j.result <- evaluateWithTimeout(ctx, evaluators[j.tc.PolicyFile], defaultContext, *j.tc, timeout)
 			}
+		}()
+	}
+	return evaluations
+}
+
+// nextEvaluation receives the next evaluation in order, false if the context is canceled first
+func nextEvaluation(ctx context.Context, evaluations <-chan chan evaluation) (evaluation, bool) {
+	select {
+	case result, ok := <-evaluations:
+		if !ok {
+			return evaluation{}, false
 		}
+		select {
+		case e := <-result:
+			return e, true
+		case <-ctx.Done():
+			return evaluation{}, false
+		}
+	case <-ctx.Done():
+		return evaluation{}, false
+	}
+}
+
+// EvaluateTestCaseTimeout is like EvaluateTestCaseContext, returning the error of the context
+// if the evaluation doesn't finish within the timeout. A timeout of 0 means no limit.
+func EvaluateTestCaseTimeout(ctx context.Context, evaluator common.Evaluator, defaultContext models.TestContext, tc models.TestCase, timeout time.Duration) (models.TestContext, common.Result, error) {
+	e := evaluateWithTimeout(ctx, evaluator, defaultContext, tc, timeout)
+	return e.mergedContext, e.result, e.err
+}
+
+// evaluateWithTimeout evaluates a test case under a deadline. Evaluations don't
+// check their context everywhere, a regular expression match can't be interrupted,
+// so the evaluation runs in its own goroutine, which is abandoned if the deadline passes.
+func evaluateWithTimeout(ctx context.Context, evaluator common.Evaluator, defaultContext models.TestContext, tc models.TestCase, timeout time.Duration) evaluation {
+	if timeout <= 0 {
+		mergedContext, result := EvaluateTestCaseContext(ctx, evaluator, defaultContext, tc)
+		return evaluation{mergedContext: mergedContext, result: result}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan evaluation, 1)
+	go func() {
+		mergedContext, result := EvaluateTestCaseContext(ctx, evaluator, defaultContext, tc)
+		done <- evaluation{mergedContext: mergedContext, result: result}
+	}()
+	select {
+	case e := <-done:
+		return e
+	case <-ctx.Done():
+		return evaluation{err: ctx.Err(), timeout: timeout}
+	}
+}
+
+// reportSkip prints a test case that wasn't evaluated
+func reportSkip(e evaluation, tc models.TestCase, outputFormat, prefix string) {
+	switch outputFormat {
+	case "efm":
+		log.Printf("%s:%d:1: %s%s: skipped: %s", tc.FileName, tc.LineNumber, prefix, tc.Name, e.skip)
+	case "pretty":
+		log.Printf("⏭️ SKIP: %s (%s)", tc.Name, e.skip)
+	}
+}
+
+// reportError prints a test case whose evaluation didn't finish
+func reportError(e evaluation, tc models.TestCase, outputFormat, prefix string) {
+	switch outputFormat {
+	case "efm":
+		log.Printf("%s:%d:1: %s%s: timed out after %s", tc.FileName, tc.LineNumber, prefix, tc.Name, e.timeout)
+	case "pretty":
+		log.Printf("⏱️ TIMEOUT: %s (after %s)", tc.Name, e.timeout)
+	}
+}
+
+// outcome is how the evaluation of a test case compared to its expectations
+type outcome int
+
+const (
+	outcomePass outcome = iota
+	outcomeFail
+	// outcomeExpectedFailure is a failing test case marked with expect_failure
+	outcomeExpectedFailure
+	// outcomeUnexpectedPass is a passing test case marked with expect_failure
+	outcomeUnexpectedPass
+)
+
+// runTestCase checks the evaluation of a single test case and prints its result.
+// The prefix is prepended to the test name in the efm output.
+func runTestCase(e evaluation, tc models.TestCase, opts Options, prefix string) outcome {
+	mergedContext, result := e.mergedContext, e.result
+	for _, observer := range opts.Observers {
+		observer.Observe(tc, mergedContext, &result)
+	}
+
+	assertionResult := CheckAssertions(tc.Assert, &result)
+	pass := assertionResult.Success()
 
-		if pass {
-			passedCount++
+	var failures []string
+	for _, checker := range opts.Checkers {
+		if failure := checker.Check(tc, &result); failure != "" {
+			failures = append(failures, failure)
+			pass = false
 		}
 	}
-	if outputFormat == "pretty" {
-		log.Printf("\nSummary: %d / %d tests passed.", passedCount, len(filteredCases))
+
+	o := outcomeFail
+	switch {
+	case tc.ExpectFailure != "" && pass:
+		o = outcomeUnexpectedPass
+	case tc.ExpectFailure != "":
+		o = outcomeExpectedFailure
+	case pass:
+		o = outcomePass
+	}
+
+	verbosity := opts.Verbosity
+	switch opts.OutputFormat {
+	case "efm":
+		switch o {
+		case outcomeFail:
+			log.Printf("%s:%d:1: %s%s", tc.FileName, tc.LineNumber, prefix, tc.Name)
+		case outcomeUnexpectedPass:
+			log.Printf("%s:%d:1: %s%s: passed unexpectedly, expected to fail: %s", tc.FileName, tc.LineNumber, prefix, tc.Name, tc.ExpectFailure)
+		}
+	case "pretty":
+		switch o {
+		case outcomePass:
+			log.Printf("✅ PASS: %s", tc.Name)
+		case outcomeFail:
+			log.Printf("❌ FAIL: %s", tc.Name)
+		case outcomeExpectedFailure:
+			log.Printf("⚠️ XFAIL: %s (%s)", tc.Name, tc.ExpectFailure)
+		case outcomeUnexpectedPass:
+			log.Printf("❗ XPASS: %s (%s)", tc.Name, tc.ExpectFailure)
+			log.Printf("  - Passed although expected to fail, remove expect_failure if the fix has landed")
+		}
+		indent := "    "
+		if o == outcomeFail || verbosity >= 1 {
+			if verbosity >= 3 {
+				log.Println("  - Test Context:")
+				output.PrintTestContext(mergedContext, indent)
+			}
+			output.PrintAssertionResult(assertionResult, verbosity, indent)
+			for _, failure := range failures {
+				output.PrintCheckFailure(failure, indent)
+			}
+			if o == outcomeFail {
+				var suggestions []string
+				for _, advisor := range opts.Advisors {
+					suggestions = append(suggestions, advisor.Advise(tc, mergedContext, &result)...)
+				}
+				output.PrintSuggestions(suggestions, indent)
+			}
+			log.Println("  - Policy Evaluation Tree:")
+			output.PrintResultTree(&result, indent, verbosity >= 3)
+		}
 	}
-	passed = passedCount == len(filteredCases)
-	return
+	return o
+}
+
+// EvaluateTestCase evaluates the context of a test case merged with the default context
+// of the file it was loaded from, or with defaultContext if it wasn't loaded from a file
+func EvaluateTestCase(evaluator common.Evaluator, defaultContext models.TestContext, tc models.TestCase) (models.TestContext, common.Result) {
+	return EvaluateTestCaseContext(context.Background(), evaluator, defaultContext, tc)
+}
+
+// EvaluateTestCaseContext is like EvaluateTestCase, evaluating under the given context
+func EvaluateTestCaseContext(ctx context.Context, evaluator common.Evaluator, defaultContext models.TestContext, tc models.TestCase) (models.TestContext, common.Result) {
+	mergedContext := models.MergeContexts(tc.DefaultContextOr(defaultContext), tc.Context)
+	pullContext := models.NewGitHubContext(mergedContext)
+	return mergedContext, evaluator.Evaluate(ctx, pullContext)
 }
 
 // CheckAssertions validates test assertions against evaluation results
@@ -118,59 +588,7 @@ func collectRuleStatuses(result *common.Result) (approved, pending, skipped []st
 	return nil, nil, nil
 }
 
-// MergeContexts merges test contexts with override precedence
+// MergeContexts merges test contexts with override precedence, see models.MergeContexts
 func MergeContexts(base, override models.TestContext) models.TestContext {
-	merged := base
-
-	if len(override.FilesChanged) > 0 {
-		merged.FilesChanged = override.FilesChanged
-	}
-	if len(override.FilesAdded) > 0 {
-		merged.FilesAdded = override.FilesAdded
-	}
-	if len(override.FilesDeleted) > 0 {
-		merged.FilesDeleted = override.FilesDeleted
-	}
-	if override.Owner != "" {
-		merged.Owner = override.Owner
-	}
-	if override.Repo != "" {
-		merged.Repo = override.Repo
-	}
-	if override.Author != "" {
-		merged.Author = override.Author
-	}
-	if override.PR.BaseRefName != "" {
-		merged.PR.BaseRefName = override.PR.BaseRefName
-	}
-	if override.PR.HeadRefName != "" {
-		merged.PR.HeadRefName = override.PR.HeadRefName
-	}
-
-	if len(override.Reviews) > 0 {
-		merged.Reviews = override.Reviews
-	}
-	if len(override.Statuses) > 0 {
-		merged.Statuses = override.Statuses
-	}
-	if len(override.WorkflowRuns) > 0 {
-		merged.WorkflowRuns = override.WorkflowRuns
-	}
-	if len(override.Labels) > 0 {
-		merged.Labels = override.Labels
-	}
-	if len(override.TeamMembers) > 0 {
-		maps.Copy(merged.TeamMembers, override.TeamMembers)
-	}
-	if len(override.OrgMembers) > 0 {
-		maps.Copy(merged.OrgMembers, override.OrgMembers)
-	}
-	if len(override.Comments) > 0 {
-		merged.Comments = override.Comments
-	}
-	if len(override.CustomProperties) > 0 {
-		maps.Copy(merged.CustomProperties, override.CustomProperties)
-	}
-
-	return merged
+	return models.MergeContexts(base, override)
 }
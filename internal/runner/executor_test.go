@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/palantir/policy-bot/pull"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// Test files with conflicting default contexts each evaluate against their own
+func TestEvaluateTestCaseUsesDefaultContextOfFile(t *testing.T) {
+	tests, err := loader.LoadTestFiles([]string{"testdata/contexts/a", "testdata/contexts/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluators, err := loader.LoadPolicyEvaluators(tests, "testdata/contexts/a/.policy.yml", loader.PolicyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests.TestCases) != 2 {
+		t.Fatalf("loaded %d test cases, expected 2", len(tests.TestCases))
+	}
+
+	for _, tc := range tests.TestCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if tc.DefaultContext == nil {
+				t.Fatal("test case has no default context")
+			}
+			merged, result := EvaluateTestCase(evaluators[tc.PolicyFile], tests.DefaultContext, tc)
+			if merged.Owner != tc.DefaultContext.Owner {
+				t.Errorf("evaluated with owner %s, expected %s", merged.Owner, tc.DefaultContext.Owner)
+			}
+			if a := CheckAssertions(tc.Assert, &result); !a.Success() {
+				t.Errorf("evaluation status %s, approved %v, expected %s", a.ActualStatus, a.ActualApproved, a.ExpectedStatus)
+			}
+		})
+	}
+}
+
+// countingEvaluator counts its evaluations, naming each result after its number
+type countingEvaluator struct {
+	started atomic.Int32
+}
+
+func (e *countingEvaluator) Trigger() common.Trigger {
+	return common.TriggerAll
+}
+
+func (e *countingEvaluator) Evaluate(context.Context, pull.Context) common.Result {
+	return common.Result{Name: strconv.Itoa(int(e.started.Add(1)))}
+}
+
+// Evaluations don't run ahead of the consumer by more than twice the number of workers
+func TestEvaluateConcurrentlyBoundsLookahead(t *testing.T) {
+	evaluator := &countingEvaluator{}
+	testCases := make([]*models.TestCase, 20)
+	for i := range testCases {
+		testCases[i] = &models.TestCase{Name: strconv.Itoa(i)}
+	}
+	evaluations := evaluateConcurrently(t.Context(), map[string]common.Evaluator{"": evaluator}, models.NewTestContext(models.TestContext{}),
+		testCases, false, Options{Parallel: 2})
+
+	time.Sleep(50 * time.Millisecond)
+	if started := evaluator.started.Load(); started != 4 {
+		t.Errorf("%d evaluations started before consuming any, expected 4", started)
+	}
+	received := 0
+	for {
+		e, ok := nextEvaluation(t.Context(), evaluations)
+		if !ok {
+			break
+		}
+		if e.result.Name == "" {
+			t.Errorf("evaluation %d has no result", received)
+		}
+		received++
+		if started := int(evaluator.started.Load()); started > received+4 {
+			t.Errorf("%d evaluations started after consuming %d", started, received)
+		}
+	}
+	if received != len(testCases) {
+		t.Errorf("received %d evaluations, expected %d", received, len(testCases))
+	}
+}
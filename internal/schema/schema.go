@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// draft is the JSON Schema version of the generated schema
+const draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is the subset of JSON Schema describing test files
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties is false for structs, or the schema of the values for maps
+	AdditionalProperties any      `json:"additionalProperties,omitempty"`
+	Items                *Schema  `json:"items,omitempty"`
+	Enum                 []string `json:"enum,omitempty"`
+
+	// order holds the property names in field order, for listing them in errors
+	order []string
+}
+
+// enum is implemented by the string types of the models with a fixed set of values
+type enum interface {
+	Values() []string
+}
+
+// TestFile returns the schema of test files
+func TestFile() *Schema {
+	s := forType(reflect.TypeFor[models.TestFile]())
+	s.Schema = draft
+	s.Title = "policy-bot-tests test file"
+	return s
+}
+
+// forType returns the schema of a Go type, based on its YAML field tags
+func forType(t reflect.Type) *Schema {
+	if t == reflect.TypeFor[time.Duration]() {
+		return &Schema{Type: "string", Description: "a duration such as 500ms or 2s"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return forType(t.Elem())
+	case reflect.String:
+		s := &Schema{Type: "string"}
+		if e, ok := reflect.Zero(t).Interface().(enum); ok {
+			s.Enum = e.Values()
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: forType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: forType(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			s.Properties[name] = forType(field.Type)
+			s.order = append(s.order, name)
+			if !strings.Contains(opts, "omitempty") {
+				s.Required = append(s.Required, name)
+			}
+		}
+		return s
+	}
+	return &Schema{}
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error is a schema violation at a position of a YAML document
+type Error struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate validates a YAML document against the schema
+func (s *Schema) Validate(node *yaml.Node) []*Error {
+	var errs []*Error
+	s.validate(node, "", &errs)
+	return errs
+}
+
+func (s *Schema) validate(node *yaml.Node, path string, errs *[]*Error) {
+	report := func(n *yaml.Node, path, format string, args ...any) {
+		if path == "" {
+			path = "(root)"
+		}
+		*errs = append(*errs, &Error{Line: n.Line, Column: n.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			s.validate(node.Content[0], path, errs)
+		}
+		return
+	case yaml.AliasNode:
+		s.validate(node.Alias, path, errs)
+		return
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			report(node, path, "expected an object")
+			return
+		}
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			seen[key.Value] = true
+			if key.Value == "<<" {
+				continue
+			}
+			keyPath := join(path, key.Value)
+			if property, ok := s.Properties[key.Value]; ok {
+				property.validate(value, keyPath, errs)
+			} else if values, ok := s.AdditionalProperties.(*Schema); ok {
+				values.validate(value, keyPath, errs)
+			} else {
+				report(key, keyPath, "unknown field, expected one of %s", strings.Join(s.order, ", "))
+			}
+		}
+		for _, name := range s.Required {
+			if !seen[name] {
+				report(node, path, "missing required field %s", name)
+			}
+		}
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			report(node, path, "expected an array")
+			return
+		}
+		for i, item := range node.Content {
+			s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case "string":
+		if node.Kind != yaml.ScalarNode {
+			report(node, path, "expected a string")
+			return
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, node.Value) {
+			report(node, path, "invalid value %q, expected one of %s", node.Value, strings.Join(s.Enum, ", "))
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			report(node, path, "expected a boolean")
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			report(node, path, "expected an integer")
+		}
+	}
+}
+
+// join appends a key to a dotted path
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,216 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// header is written at the top of every snapshot file
+const header = "# Policy evaluation snapshots, update with: policy-bot-tests verify --update-snapshots\n"
+
+// Node is a node of a snapshotted policy evaluation tree
+type Node struct {
+	Name        string  `yaml:"name"`
+	Status      string  `yaml:"status"`
+	Description string  `yaml:"description,omitempty"`
+	Children    []*Node `yaml:"children,omitempty"`
+}
+
+// NewNode creates a snapshot of a policy evaluation result tree
+func NewNode(result *common.Result) *Node {
+	n := &Node{
+		Name:        result.Name,
+		Status:      result.Status.String(),
+		Description: result.StatusDescription,
+	}
+	for _, child := range result.Children {
+		n.Children = append(n.Children, NewNode(child))
+	}
+	return n
+}
+
+// lines renders the tree as indented lines, used for diffing
+func (n *Node) lines(indent string) []string {
+	line := fmt.Sprintf("%s%s %s", indent, n.Status, n.Name)
+	if n.Description != "" {
+		line += ": " + n.Description
+	}
+	lines := []string{line}
+	for _, child := range n.Children {
+		lines = append(lines, child.lines(indent+"  ")...)
+	}
+	return lines
+}
+
+// File is the content of a snapshot file, holding the snapshots of a test file by test case name
+type File struct {
+	Snapshots map[string]*Node `yaml:"snapshots"`
+}
+
+// FileName returns the snapshot file stored next to a test file
+func FileName(testFile string) string {
+	for _, ext := range []string{".yml", ".yaml"} {
+		if base, ok := strings.CutSuffix(testFile, ext); ok {
+			return base + ".snap" + ext
+		}
+	}
+	return testFile + ".snap.yml"
+}
+
+// Store compares evaluation results with the snapshots stored next to the test files.
+// It implements runner.Checker.
+type Store struct {
+	// All enables snapshots for every test case, not only the ones with snapshot: true
+	All bool
+	// Update rewrites mismatching snapshots instead of failing
+	Update bool
+
+	files   map[string]*File
+	changed map[string]bool
+	// lines holds the line of the test case checked under each name, by snapshot file
+	lines map[string]map[string]int
+	// Written lists the names of the test cases whose snapshots were written
+	Written []string
+}
+
+// NewStore creates a snapshot store
+func NewStore(all, update bool) *Store {
+	return &Store{
+		All:     all,
+		Update:  update,
+		files:   map[string]*File{},
+		changed: map[string]bool{},
+		lines:   map[string]map[string]int{},
+	}
+}
+
+// Check compares the evaluation result with the stored snapshot of the test case.
+// Missing snapshots are written, mismatching snapshots fail unless updating.
+// Snapshots are stored by test case name, so test cases sharing a name in a file fail.
+func (s *Store) Check(tc models.TestCase, result *common.Result) string {
+	if !s.All && !tc.Snapshot {
+		return ""
+	}
+	fileName := FileName(tc.FileName)
+	file, err := s.load(fileName)
+	if err != nil {
+		return fmt.Sprintf("Failed to load snapshot: %v", err)
+	}
+	if s.lines[fileName] == nil {
+		s.lines[fileName] = map[string]int{}
+	}
+	// Repeated runs of the same test case share its snapshot
+	if line, ok := s.lines[fileName][tc.Name]; ok && line != tc.LineNumber {
+		return fmt.Sprintf("Duplicate test name %q, first defined at %s:%d, snapshots are stored by test case name", tc.Name, tc.FileName, line)
+	}
+	s.lines[fileName][tc.Name] = tc.LineNumber
+
+	actual := NewNode(result)
+	expected, ok := file.Snapshots[tc.Name]
+	if ok {
+		diff := diffLines(expected.lines(""), actual.lines(""))
+		if diff == "" {
+			return ""
+		}
+		if !s.Update {
+			return "Snapshot mismatch (- snapshot, + actual):\n" + diff
+		}
+	}
+	file.Snapshots[tc.Name] = actual
+	s.changed[fileName] = true
+	s.Written = append(s.Written, tc.Name)
+	return ""
+}
+
+// load reads a snapshot file, or returns an empty one if it doesn't exist
+func (s *Store) load(fileName string) (*File, error) {
+	if file, ok := s.files[fileName]; ok {
+		return file, nil
+	}
+	file := &File{}
+	content, err := os.ReadFile(fileName)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := yaml.Unmarshal(content, file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", fileName, err)
+		}
+	}
+	if file.Snapshots == nil {
+		file.Snapshots = map[string]*Node{}
+	}
+	s.files[fileName] = file
+	return file, nil
+}
+
+// Save writes all snapshot files with new or updated snapshots
+func (s *Store) Save() error {
+	var fileNames []string
+	for fileName := range s.changed {
+		fileNames = append(fileNames, fileName)
+	}
+	slices.Sort(fileNames)
+	for _, fileName := range fileNames {
+		var buf bytes.Buffer
+		buf.WriteString(header)
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(s.files[fileName]); err != nil {
+			return fmt.Errorf("failed to marshal snapshots: %w", err)
+		}
+		if err := os.WriteFile(fileName, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", fileName, err)
+		}
+	}
+	return nil
+}
+
+// diffLines returns a line diff of two texts, or an empty string if they are equal
+func diffLines(expected, actual []string) string {
+	if slices.Equal(expected, actual) {
+		return ""
+	}
+	// lcs[i][j] is the length of the longest common subsequence of expected[i:] and actual[j:]
+	lcs := make([][]int, len(expected)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(actual)+1)
+	}
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			diff = append(diff, "  "+expected[i])
+			i++
+			j++
+		case i < len(expected) && (j == len(actual) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+expected[i])
+			i++
+		default:
+			diff = append(diff, "+ "+actual[j])
+			j++
+		}
+	}
+	return strings.Join(diff, "\n")
+}
@@ -0,0 +1,38 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+func TestCheckDuplicateNames(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "a.policy-tests.yml")
+	result := &common.Result{Name: "policy", Status: common.StatusApproved}
+	store := NewStore(true, false)
+
+	first := models.TestCase{Name: "a", FileName: fileName, LineNumber: 2}
+	for range 2 {
+		if failure := store.Check(first, result); failure != "" {
+			t.Fatalf("repeated run failed: %s", failure)
+		}
+	}
+	if failure := store.Check(models.TestCase{Name: "b", FileName: fileName, LineNumber: 5}, result); failure != "" {
+		t.Fatalf("other test case failed: %s", failure)
+	}
+
+	duplicate := models.TestCase{Name: "a", FileName: fileName, LineNumber: 8}
+	failure := store.Check(duplicate, &common.Result{Name: "policy", Status: common.StatusPending})
+	if want := `Duplicate test name "a", first defined at ` + fileName + ":2"; !strings.HasPrefix(failure, want) {
+		t.Errorf("failure %q, expected %q", failure, want)
+	}
+	if want := []string{"a", "b"}; strings.Join(store.Written, ",") != strings.Join(want, ",") {
+		t.Errorf("wrote %v, expected %v", store.Written, want)
+	}
+	if status := store.files[FileName(fileName)].Snapshots["a"].Status; status != "approved" {
+		t.Errorf("snapshot status %s, expected approved", status)
+	}
+}
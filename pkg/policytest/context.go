@@ -0,0 +1,133 @@
+package policytest
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/reegnz/policy-bot-tests/internal/models"
+)
+
+// ContextBuilder builds a test context in code, like the context of a test file
+type ContextBuilder struct {
+	tc TestContext
+}
+
+// NewContext starts building a test context for a pull request of owner/repo
+func NewContext(owner, repo string) *ContextBuilder {
+	return &ContextBuilder{tc: models.NewTestContext(TestContext{Owner: owner, Repo: repo})}
+}
+
+// Author sets the author of the pull request
+func (b *ContextBuilder) Author(author string) *ContextBuilder {
+	b.tc.Author = author
+	return b
+}
+
+// Branches sets the base and head branches of the pull request
+func (b *ContextBuilder) Branches(base, head string) *ContextBuilder {
+	b.tc.PR = TestPullRequest{BaseRefName: base, HeadRefName: head}
+	return b
+}
+
+// FilesChanged adds modified files
+func (b *ContextBuilder) FilesChanged(files ...string) *ContextBuilder {
+	b.tc.FilesChanged = append(b.tc.FilesChanged, files...)
+	return b
+}
+
+// FilesAdded adds added files
+func (b *ContextBuilder) FilesAdded(files ...string) *ContextBuilder {
+	b.tc.FilesAdded = append(b.tc.FilesAdded, files...)
+	return b
+}
+
+// FilesDeleted adds deleted files
+func (b *ContextBuilder) FilesDeleted(files ...string) *ContextBuilder {
+	b.tc.FilesDeleted = append(b.tc.FilesDeleted, files...)
+	return b
+}
+
+// Review adds a review
+func (b *ContextBuilder) Review(author string, state ReviewState) *ContextBuilder {
+	b.tc.Reviews = append(b.tc.Reviews, TestReview{Author: author, State: state})
+	return b
+}
+
+// Approve adds an approving review
+func (b *ContextBuilder) Approve(author string) *ContextBuilder {
+	return b.Review(author, ReviewApproved)
+}
+
+// Comment adds a comment
+func (b *ContextBuilder) Comment(author, body string) *ContextBuilder {
+	b.tc.Comments = append(b.tc.Comments, TestComment{Author: author, Body: body})
+	return b
+}
+
+// Status sets the latest conclusion of a commit status or check run
+func (b *ContextBuilder) Status(name string, conclusion Conclusion) *ContextBuilder {
+	b.tc.Statuses[name] = conclusion
+	return b
+}
+
+// WorkflowRun adds the conclusions of runs of a workflow
+func (b *ContextBuilder) WorkflowRun(workflow string, conclusions ...Conclusion) *ContextBuilder {
+	b.tc.WorkflowRuns[workflow] = append(b.tc.WorkflowRuns[workflow], conclusions...)
+	return b
+}
+
+// Labels adds labels
+func (b *ContextBuilder) Labels(labels ...string) *ContextBuilder {
+	b.tc.Labels = append(b.tc.Labels, labels...)
+	return b
+}
+
+// Team adds members to an org/team, team members are also write collaborators
+func (b *ContextBuilder) Team(team string, members ...string) *ContextBuilder {
+	b.tc.TeamMembers[team] = append(b.tc.TeamMembers[team], members...)
+	return b
+}
+
+// Org adds members to an organization
+func (b *ContextBuilder) Org(org string, members ...string) *ContextBuilder {
+	b.tc.OrgMembers[org] = append(b.tc.OrgMembers[org], members...)
+	return b
+}
+
+// CustomProperty sets a string custom property of the repository
+func (b *ContextBuilder) CustomProperty(name, value string) *ContextBuilder {
+	b.tc.CustomProperties[name] = TestCustomProperty{String: &value}
+	return b
+}
+
+// CustomPropertyArray sets a multi-select custom property of the repository
+func (b *ContextBuilder) CustomPropertyArray(name string, values ...string) *ContextBuilder {
+	b.tc.CustomProperties[name] = TestCustomProperty{Array: values}
+	return b
+}
+
+// Build returns the test context. The builder can be changed further without affecting it.
+func (b *ContextBuilder) Build() TestContext {
+	tc := b.tc
+	tc.FilesChanged = slices.Clone(tc.FilesChanged)
+	tc.FilesAdded = slices.Clone(tc.FilesAdded)
+	tc.FilesDeleted = slices.Clone(tc.FilesDeleted)
+	tc.Reviews = slices.Clone(tc.Reviews)
+	tc.Comments = slices.Clone(tc.Comments)
+	tc.Labels = slices.Clone(tc.Labels)
+	tc.Statuses = maps.Clone(tc.Statuses)
+	tc.WorkflowRuns = cloneLists(tc.WorkflowRuns)
+	tc.TeamMembers = cloneLists(tc.TeamMembers)
+	tc.OrgMembers = cloneLists(tc.OrgMembers)
+	tc.CustomProperties = maps.Clone(tc.CustomProperties)
+	return tc
+}
+
+// cloneLists deeply copies a map of lists
+func cloneLists[T any](m map[string][]T) map[string][]T {
+	clone := make(map[string][]T, len(m))
+	for k, v := range m {
+		clone[k] = slices.Clone(v)
+	}
+	return clone
+}
@@ -0,0 +1,231 @@
+package policytest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/palantir/policy-bot/policy/common"
+	"github.com/reegnz/policy-bot-tests/internal/loader"
+	"github.com/reegnz/policy-bot-tests/internal/models"
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+)
+
+// Types of the test files, see the README for their YAML representation
+type (
+	TestFile           = models.TestFile
+	TestCase           = models.TestCase
+	TestContext        = models.TestContext
+	TestPullRequest    = models.TestPullRequest
+	TestReview         = models.TestReview
+	TestComment        = models.TestComment
+	TestAssertion      = models.TestAssertion
+	TestCustomProperty = models.TestCustomProperty
+	AssertionResult    = models.AssertionResult
+
+	ReviewState      = models.ReviewState
+	EvaluationStatus = models.EvaluationStatus
+	Conclusion       = models.Conclusion
+)
+
+// Review states and evaluation statuses
+const (
+	ReviewApproved         = models.ReviewApproved
+	ReviewChangesRequested = models.ReviewChangesRequested
+	ReviewCommented        = models.ReviewCommented
+	ReviewDismissed        = models.ReviewDismissed
+	ReviewPending          = models.ReviewPending
+
+	StatusApproved    = models.StatusApproved
+	StatusPending     = models.StatusPending
+	StatusDisapproved = models.StatusDisapproved
+	StatusSkipped     = models.StatusSkipped
+)
+
+// DefaultPolicyFile is the policy of test cases not declaring one, unless SuiteOptions overrides it
+const DefaultPolicyFile = ".policy.yml"
+
+// SuiteOptions controls how a suite loads its policies
+type SuiteOptions struct {
+	// PolicyFile is the policy of test cases not declaring one, DefaultPolicyFile if empty
+	PolicyFile string
+	// RemoteRoots resolves remote policy references of an org/repo to a local directory
+	RemoteRoots map[string]string
+}
+
+// Suite is a set of test cases, along with the policies they are evaluated against.
+// It is safe for concurrent use.
+type Suite struct {
+	// DefaultContext is the default context of test cases not loaded from a test file,
+	// the ones loaded from a file use the default context of their file
+	DefaultContext TestContext
+	TestCases      []TestCase
+
+	defaultPolicy string
+	policyOptions loader.PolicyOptions
+
+	mu         sync.Mutex
+	evaluators map[string]common.Evaluator
+}
+
+// LoadSuite loads the test files at the paths, directories are searched for
+// *.policy-tests.yml files, and the policies their test cases are bound to
+func LoadSuite(paths []string, opts SuiteOptions) (*Suite, error) {
+	tests, err := loader.LoadTestFiles(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tests: %w", err)
+	}
+	return NewSuite(tests, opts)
+}
+
+// NewSuite creates a suite of already loaded test files, loading the policies their test cases are bound to
+func NewSuite(tests *TestFile, opts SuiteOptions) (*Suite, error) {
+	if opts.PolicyFile == "" {
+		opts.PolicyFile = DefaultPolicyFile
+	}
+	s := &Suite{
+		DefaultContext: models.NewTestContext(tests.DefaultContext),
+		defaultPolicy:  filepath.Clean(opts.PolicyFile),
+		policyOptions:  loader.PolicyOptions{RemoteRoots: opts.RemoteRoots},
+	}
+	evaluators, err := loader.LoadPolicyEvaluators(tests, s.defaultPolicy, s.policyOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy: %w", err)
+	}
+	s.TestCases = tests.TestCases
+	s.evaluators = evaluators
+	return s, nil
+}
+
+// evaluator returns the evaluator of a policy file, loading it if no test case of the suite uses it
+func (s *Suite) evaluator(policyFile string) (common.Evaluator, error) {
+	if policyFile == "" {
+		policyFile = s.defaultPolicy
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if evaluator, ok := s.evaluators[policyFile]; ok {
+		return evaluator, nil
+	}
+	evaluator, err := loader.LoadPolicyEvaluator(policyFile, s.policyOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy: %w", err)
+	}
+	s.evaluators[policyFile] = evaluator
+	return evaluator, nil
+}
+
+// Outcome is how the evaluation of a test case compared to its expectations
+type Outcome int
+
+const (
+	Passed Outcome = iota
+	Failed
+	// ExpectedFailure is a failing test case marked with expect_failure
+	ExpectedFailure
+	// UnexpectedPass is a passing test case marked with expect_failure
+	UnexpectedPass
+	// Skipped is a test case that wasn't evaluated, see Result.SkipReason
+	Skipped
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Passed:
+		return "passed"
+	case Failed:
+		return "failed"
+	case ExpectedFailure:
+		return "failed as expected"
+	case UnexpectedPass:
+		return "passed unexpectedly"
+	case Skipped:
+		return "skipped"
+	}
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
+// OK reports whether the outcome doesn't fail a run
+func (o Outcome) OK() bool {
+	return o == Passed || o == ExpectedFailure || o == Skipped
+}
+
+// Result is the evaluation result of a test case
+type Result struct {
+	TestCase TestCase
+	Outcome  Outcome
+	// SkipReason is why the test case wasn't evaluated, if it was skipped
+	SkipReason string
+	// Context is the context of the test case merged with its default context
+	Context TestContext
+	// Evaluation is the result of the policy evaluation, nil if the test case was skipped
+	// or its evaluation didn't finish
+	Evaluation *common.Result
+	// Err is the error of the evaluation if it didn't finish within the timeout of the test case
+	Err error
+	// Assertions compares the assertions of the test case with the evaluation
+	Assertions AssertionResult
+}
+
+// Evaluate evaluates a test case against the policy it is bound to, the policy of
+// the suite if it doesn't declare one. Skip markers are not honored, see Run.
+// A test case whose evaluation doesn't finish within its timeout fails, an error
+// is only returned if the policy can't be loaded or the context is canceled.
+func (s *Suite) Evaluate(ctx context.Context, tc TestCase) (Result, error) {
+	evaluator, err := s.evaluator(tc.PolicyFile)
+	if err != nil {
+		return Result{}, err
+	}
+	mergedContext, evaluation, err := runner.EvaluateTestCaseTimeout(ctx, evaluator, s.DefaultContext, tc, tc.Timeout)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Result{}, ctx.Err()
+		}
+		return Result{TestCase: tc, Outcome: Failed, Err: err}, nil
+	}
+	assertions := runner.CheckAssertions(tc.Assert, &evaluation)
+
+	r := Result{TestCase: tc, Context: mergedContext, Evaluation: &evaluation, Assertions: assertions, Outcome: Failed}
+	pass := assertions.Success()
+	switch {
+	case tc.ExpectFailure != "" && pass:
+		r.Outcome = UnexpectedPass
+	case tc.ExpectFailure != "":
+		r.Outcome = ExpectedFailure
+	case pass:
+		r.Outcome = Passed
+	}
+	return r, nil
+}
+
+// Failure describes the assertions of the test case that failed, or why it
+// passed unexpectedly. It is empty for other outcomes.
+func (r Result) Failure() string {
+	if r.Err != nil {
+		return fmt.Sprintf("evaluation didn't finish within %s", r.TestCase.Timeout)
+	}
+	if r.Outcome == UnexpectedPass {
+		return fmt.Sprintf("passed although expected to fail (%s), remove expect_failure if the fix has landed", r.TestCase.ExpectFailure)
+	}
+	if r.Outcome != Failed {
+		return ""
+	}
+
+	a := r.Assertions
+	var failures []string
+	if !a.MatchesStatus() {
+		failures = append(failures, fmt.Sprintf("evaluation status is %s, expected %s", a.ActualStatus, a.ExpectedStatus))
+	}
+	if missing := a.MissingApproved(); len(missing) > 0 {
+		failures = append(failures, "rules not approved: "+strings.Join(missing, ", "))
+	}
+	if missing := a.MissingPending(); len(missing) > 0 {
+		failures = append(failures, "rules not pending: "+strings.Join(missing, ", "))
+	}
+	if missing := a.MissingSkipped(); len(missing) > 0 {
+		failures = append(failures, "rules not skipped: "+strings.Join(missing, ", "))
+	}
+	return strings.Join(failures, "; ")
+}
@@ -0,0 +1,201 @@
+package policytest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reegnz/policy-bot-tests/pkg/policytest"
+)
+
+func TestPolicy(t *testing.T) {
+	policytest.Run(t, "../../tests/.policy.yml", "../../tests")
+}
+
+func loadSuite(t *testing.T) *policytest.Suite {
+	t.Helper()
+	suite, err := policytest.LoadSuite([]string{"../../tests"}, policytest.SuiteOptions{PolicyFile: "../../tests/.policy.yml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return suite
+}
+
+func TestLoadSuite(t *testing.T) {
+	suite := loadSuite(t)
+	if len(suite.TestCases) != 8 {
+		t.Errorf("loaded %d test cases, expected 8", len(suite.TestCases))
+	}
+	if suite.DefaultContext.Owner != "test" {
+		t.Errorf("default context owner %q, expected test", suite.DefaultContext.Owner)
+	}
+
+	if _, err := policytest.LoadSuite([]string{"../../tests/missing"}, policytest.SuiteOptions{}); err == nil {
+		t.Error("expected an error for a missing test path")
+	}
+	if _, err := policytest.LoadSuite([]string{"../../tests"}, policytest.SuiteOptions{PolicyFile: "missing.yml"}); err == nil {
+		t.Error("expected an error for a missing policy")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	suite := loadSuite(t)
+	alphaApproves := policytest.NewContext("test", "test").
+		Author("dev").
+		FilesChanged("team-alpha/main.go").
+		Team("test/team-alpha", "alpha-alice").
+		Approve("alpha-alice").
+		Build()
+	approved := policytest.TestAssertion{EvaluationStatus: policytest.StatusApproved}
+	pending := policytest.TestAssertion{EvaluationStatus: policytest.StatusPending}
+
+	for _, test := range []struct {
+		name    string
+		tc      policytest.TestCase
+		want    policytest.Outcome
+		failure string
+	}{
+		{
+			name: "passed",
+			tc:   policytest.TestCase{Name: "alpha approves", Context: alphaApproves, Assert: approved},
+			want: policytest.Passed,
+		},
+		{
+			name:    "failed",
+			tc:      policytest.TestCase{Name: "alpha approves", Context: alphaApproves, Assert: pending},
+			want:    policytest.Failed,
+			failure: "evaluation status is approved, expected pending",
+		},
+		{
+			name: "expected failure",
+			tc:   policytest.TestCase{Name: "alpha approves", Context: alphaApproves, Assert: pending, ExpectFailure: "known gap"},
+			want: policytest.ExpectedFailure,
+		},
+		{
+			name:    "unexpected pass",
+			tc:      policytest.TestCase{Name: "alpha approves", Context: alphaApproves, Assert: approved, ExpectFailure: "known gap"},
+			want:    policytest.UnexpectedPass,
+			failure: "passed although expected to fail (known gap)",
+		},
+		{
+			name:    "timed out",
+			tc:      policytest.TestCase{Name: "alpha approves", Context: alphaApproves, Assert: approved, Timeout: time.Nanosecond},
+			want:    policytest.Failed,
+			failure: "evaluation didn't finish within 1ns",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := suite.Evaluate(t.Context(), test.tc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Outcome != test.want {
+				t.Errorf("outcome %s, expected %s", r.Outcome, test.want)
+			}
+			if failure := r.Failure(); !strings.HasPrefix(failure, test.failure) || (test.failure == "") != (failure == "") {
+				t.Errorf("failure %q, expected %q", failure, test.failure)
+			}
+		})
+	}
+}
+
+// Contexts built by hand, without NewContext, have nil maps
+func TestEvaluateHandBuiltDefaultContext(t *testing.T) {
+	suite := loadSuite(t)
+	suite.DefaultContext = policytest.TestContext{Owner: "test", Repo: "test", PR: policytest.TestPullRequest{BaseRefName: "main"}}
+	tc := policytest.TestCase{
+		Name: "alpha approves",
+		Context: policytest.NewContext("", "").
+			Author("dev").
+			FilesChanged("team-alpha/main.go").
+			Team("test/team-alpha", "alpha-alice").
+			Approve("alpha-alice").
+			Build(),
+		Assert:  policytest.TestAssertion{EvaluationStatus: policytest.StatusApproved},
+		Timeout: time.Minute,
+	}
+	for _, defaultContext := range []*policytest.TestContext{nil, &suite.DefaultContext} {
+		tc.DefaultContext = defaultContext
+		r, err := suite.Evaluate(t.Context(), tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Outcome != policytest.Passed {
+			t.Errorf("outcome %s, expected passed: %s", r.Outcome, r.Failure())
+		}
+	}
+}
+
+// recorder is a reporter recording the results and summary of a run
+type recorder struct {
+	results  []policytest.Result
+	summary  policytest.Summary
+	finished bool
+}
+
+func (r *recorder) Report(result policytest.Result) {
+	r.results = append(r.results, result)
+}
+
+func (r *recorder) Finish(s policytest.Summary) {
+	r.summary = s
+	r.finished = true
+}
+
+func TestRun(t *testing.T) {
+	suite := loadSuite(t)
+
+	t.Run("all", func(t *testing.T) {
+		var rec recorder
+		summary, err := suite.Run(t.Context(), policytest.RunOptions{}, &rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rec.finished || rec.summary != summary {
+			t.Errorf("reporter finished %t with %+v, expected %+v", rec.finished, rec.summary, summary)
+		}
+		if want := (policytest.Summary{Total: 8, Passed: 8}); summary != want || !summary.OK() {
+			t.Errorf("summary %+v, expected %+v", summary, want)
+		}
+		for i, r := range rec.results {
+			if r.TestCase.Name != suite.TestCases[i].Name {
+				t.Errorf("result %d is of %q, expected %q", i, r.TestCase.Name, suite.TestCases[i].Name)
+			}
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		var rec recorder
+		summary, err := suite.Run(t.Context(), policytest.RunOptions{Filter: "^Comment approval"}, &rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.Total != 2 || len(rec.results) != 2 {
+			t.Errorf("ran %d test cases, reported %d, expected 2", summary.Total, len(rec.results))
+		}
+	})
+
+	t.Run("skip markers", func(t *testing.T) {
+		skipped := loadSuite(t)
+		skipped.TestCases[0].Skip = "flaky"
+		skipped.TestCases[1].ExpectFailure = "known gap"
+		skipped.TestCases[1].Assert.EvaluationStatus = policytest.StatusApproved
+		summary, err := skipped.Run(t.Context(), policytest.RunOptions{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (policytest.Summary{Total: 8, Passed: 6, ExpectedFailures: 1, Skipped: 1}); summary != want {
+			t.Errorf("summary %+v, expected %+v", summary, want)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := suite.Run(t.Context(), policytest.RunOptions{Filter: "("}, nil); !errors.Is(err, policytest.ErrInvalidFilter) {
+			t.Errorf("got error %v, expected %v", err, policytest.ErrInvalidFilter)
+		}
+		if _, err := suite.Run(t.Context(), policytest.RunOptions{Tags: []string{"missing"}}, nil); !errors.Is(err, policytest.ErrNoTestsMatched) {
+			t.Errorf("got error %v, expected %v", err, policytest.ErrNoTestsMatched)
+		}
+	})
+}
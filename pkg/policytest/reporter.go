@@ -0,0 +1,95 @@
+package policytest
+
+import (
+	"context"
+
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+)
+
+// Errors returned by Run before running any test case
+var (
+	ErrInvalidFilter  = runner.ErrInvalidFilter
+	ErrNoTestsMatched = runner.ErrNoTestsMatched
+)
+
+// Reporter receives the results of a run, in test case order
+type Reporter interface {
+	Report(r Result)
+	Finish(s Summary)
+}
+
+// Summary counts the outcomes of a run
+type Summary struct {
+	Total            int
+	Passed           int
+	Failed           int
+	ExpectedFailures int
+	UnexpectedPasses int
+	Skipped          int
+}
+
+// OK reports whether no test case failed or passed unexpectedly
+func (s Summary) OK() bool {
+	return s.Failed == 0 && s.UnexpectedPasses == 0
+}
+
+func (s *Summary) add(o Outcome) {
+	s.Total++
+	switch o {
+	case Passed:
+		s.Passed++
+	case Failed:
+		s.Failed++
+	case ExpectedFailure:
+		s.ExpectedFailures++
+	case UnexpectedPass:
+		s.UnexpectedPasses++
+	case Skipped:
+		s.Skipped++
+	}
+}
+
+// RunOptions selects the test cases of a run, like the flags of the verify command
+type RunOptions struct {
+	// Filter selects the test cases with names matching the regex
+	Filter string
+	// Tags selects the test cases with any of the tags, ExcludeTags drops the ones with any of the tags
+	Tags        []string
+	ExcludeTags []string
+}
+
+// Run evaluates the selected test cases of the suite in order, honoring their
+// skip and only markers and timeouts, and reports every result to the reporter, which
+// may be nil. It stops with the error of the context if it is canceled.
+func (s *Suite) Run(ctx context.Context, opts RunOptions, reporter Reporter) (Summary, error) {
+	var summary Summary
+	testCases, err := runner.SelectTestCases(s.TestCases, runner.Options{
+		Filter:      opts.Filter,
+		Tags:        opts.Tags,
+		ExcludeTags: opts.ExcludeTags,
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	focused := runner.IsFocused(testCases)
+	for _, tc := range testCases {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		r := Result{TestCase: tc, Outcome: Skipped, SkipReason: runner.SkipReason(tc, focused)}
+		if r.SkipReason == "" {
+			if r, err = s.Evaluate(ctx, tc); err != nil {
+				return summary, err
+			}
+		}
+		summary.add(r.Outcome)
+		if reporter != nil {
+			reporter.Report(r)
+		}
+	}
+	if reporter != nil {
+		reporter.Finish(summary)
+	}
+	return summary, nil
+}
@@ -0,0 +1,49 @@
+package policytest
+
+import (
+	"testing"
+
+	"github.com/reegnz/policy-bot-tests/internal/runner"
+)
+
+// DefaultTestPath is where Run looks for test files if no test paths are given
+const DefaultTestPath = ".policy-tests"
+
+// Run runs the test cases of the test files as subtests of t, so they can be selected
+// with -run and are listed with -v. Test cases not declaring a policy are evaluated
+// against policyPath, DefaultPolicyFile if empty. Test cases marked with skip, or not
+// marked with only while others are, are skipped, and so are expected failures.
+// Test cases whose evaluation doesn't finish within their timeout fail.
+func Run(t *testing.T, policyPath string, testPaths ...string) {
+	t.Helper()
+	if len(testPaths) == 0 {
+		testPaths = []string{DefaultTestPath}
+	}
+	suite, err := LoadSuite(testPaths, SuiteOptions{PolicyFile: policyPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	focused := runner.IsFocused(suite.TestCases)
+	for _, tc := range suite.TestCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Helper()
+			if reason := runner.SkipReason(tc, focused); reason != "" {
+				t.Skip(reason)
+			}
+			r, err := suite.Evaluate(t.Context(), tc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Evaluation != nil {
+				t.Logf("%s:%d: evaluation status %s", tc.FileName, tc.LineNumber, r.Assertions.ActualStatus)
+			}
+			switch r.Outcome {
+			case Failed, UnexpectedPass:
+				t.Errorf("%s:%d: %s", tc.FileName, tc.LineNumber, r.Failure())
+			case ExpectedFailure:
+				t.Skipf("failed as expected: %s", tc.ExpectFailure)
+			}
+		})
+	}
+}